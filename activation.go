@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Files written into a project directory for per-project activation
+const (
+	envrcFileName          = ".envrc"
+	activationScriptName   = "ai-menu-activate.sh"
+	activationFishFileName = "ai-menu-activate.fish"
+)
+
+// environmentBinDirs returns the directories to put on PATH for an environment, highest
+// priority first: the shim directory, the default pixi prefix, then isolated tool prefixes
+func environmentBinDirs(env EnvironmentRecord) []string {
+	dirs := []string{
+		shimBinDir(env.Path),
+		filepath.Join(env.Path, ".pixi", "envs", "default", "bin"),
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(env.Path, ".pixi", "envs", "*", "bin"))
+	sort.Strings(matches)
+	for _, match := range matches {
+		if !containsString(dirs, match) {
+			dirs = append(dirs, match)
+		}
+	}
+	return dirs
+}
+
+// activationLines returns the statements that activate the environment in the given shell
+func activationLines(shell string, env EnvironmentRecord) []string {
+	dirs := environmentBinDirs(env)
+
+	if shell == shellFish {
+		quoted := make([]string, 0, len(dirs))
+		for _, dir := range dirs {
+			quoted = append(quoted, fmt.Sprintf("%q", dir))
+		}
+		return []string{
+			fmt.Sprintf("set -gx PATH %s $PATH", strings.Join(quoted, " ")),
+			fmt.Sprintf("set -gx AI_MENU_ENV %q", env.Name),
+		}
+	}
+
+	return []string{
+		fmt.Sprintf("export PATH=\"%s:$PATH\"", strings.Join(dirs, ":")),
+		fmt.Sprintf("export AI_MENU_ENV=%q", env.Name),
+	}
+}
+
+// ActivationSnippet returns a snippet that can be evaluated by the shell to activate the environment
+func ActivationSnippet(shell string, env EnvironmentRecord) string {
+	return strings.Join(activationLines(shell, env), "\n") + "\n"
+}
+
+// envrcLines returns the direnv statements that activate the environment.
+// PATH_add prepends, so the directories are added lowest priority first.
+func envrcLines(env EnvironmentRecord) []string {
+	dirs := environmentBinDirs(env)
+	lines := make([]string, 0, len(dirs)+1)
+	for i := len(dirs) - 1; i >= 0; i-- {
+		lines = append(lines, fmt.Sprintf("PATH_add %q", dirs[i]))
+	}
+	return append(lines, fmt.Sprintf("export AI_MENU_ENV=%q", env.Name))
+}
+
+// WriteEnvrc writes the environment's activation into the project's .envrc for direnv,
+// keeping anything else the file contains
+func WriteEnvrc(projectDir string, env EnvironmentRecord) (string, error) {
+	path := filepath.Join(projectDir, envrcFileName)
+	return path, writeManagedBlockAt(path, envrcLines(env))
+}
+
+// WriteActivationScripts writes sourceable POSIX and fish activation scripts into the project
+func WriteActivationScripts(projectDir string, env EnvironmentRecord) ([]string, error) {
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		return nil, err
+	}
+
+	scripts := map[string]string{
+		activationScriptName:   shellBash,
+		activationFishFileName: shellFish,
+	}
+
+	paths := []string{}
+	for _, name := range []string{activationScriptName, activationFishFileName} {
+		path := filepath.Join(projectDir, name)
+		content := fmt.Sprintf("# Generated by ai-menu for environment %s. Source this file to activate it.\n%s",
+			env.Name, ActivationSnippet(scripts[name], env))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
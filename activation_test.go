@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEnvrcLines(t *testing.T) {
+	envPath := t.TempDir()
+	for _, name := range []string{"default", "codex"} {
+		if err := os.MkdirAll(filepath.Join(envPath, ".pixi", "envs", name, "bin"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shims := shimBinDir(envPath)
+	defaultBin := filepath.Join(envPath, ".pixi", "envs", "default", "bin")
+	codexBin := filepath.Join(envPath, ".pixi", "envs", "codex", "bin")
+
+	tests := []struct {
+		name string
+		env  EnvironmentRecord
+		want []string
+	}{
+		{"without isolated tools", EnvironmentRecord{Name: "empty", Path: "/nowhere/empty"}, []string{
+			`PATH_add "/nowhere/empty/.pixi/envs/default/bin"`,
+			`PATH_add "/nowhere/empty/bin"`,
+			`export AI_MENU_ENV="empty"`,
+		}},
+		// PATH_add prepends, so the shims are added last to end up first on PATH
+		{"with an isolated tool", EnvironmentRecord{Name: "team", Path: envPath}, []string{
+			`PATH_add "` + codexBin + `"`,
+			`PATH_add "` + defaultBin + `"`,
+			`PATH_add "` + shims + `"`,
+			`export AI_MENU_ENV="team"`,
+		}},
+	}
+	for _, tt := range tests {
+		if got := envrcLines(tt.env); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: envrcLines = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// The shell activation keeps the highest priority directory first
+	want := `export PATH="` + shims + ":" + defaultBin + ":" + codexBin + `:$PATH"`
+	if got := activationLines(shellBash, tests[1].env)[0]; got != want {
+		t.Errorf("activationLines = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// legacyAliasFilePath returns the per-shell alias file written by older versions
+func legacyAliasFilePath(shell string) (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aliases."+shell), nil
+}
+
+// toolRecordsFor converts successful install results into tool records for the state file
+func toolRecordsFor(category string, results []InstallResult) []ToolRecord {
+	records := make([]ToolRecord, 0, len(results))
+	for _, result := range results {
+		if !result.Success {
+			continue
+		}
+
+		switch category {
+		case categoryCLI, categoryEnhancer:
+			records = append(records, ToolRecord{
+				Name:     result.Name,
+				Category: category,
+				Alias:    getAliasName(result.Name),
+				Command:  getCommandName(result.Name),
+				PixiEnv:  result.Environment,
+				Version:  result.Version,
+			})
+		case categorySpecial:
+			// modal is the only special tool that lives inside the pixi environment
+			if result.Name == "modal" {
+				records = append(records, ToolRecord{
+					Name:     result.Name,
+					Category: category,
+					Alias:    "modal",
+					Command:  "python -m modal",
+					Version:  result.Version,
+				})
+			}
+		}
+	}
+	return records
+}
+
+// namedCommand is a command exposed to the user under a short name, as an alias or a shim
+type namedCommand struct {
+	Name    string
+	Command string
+	Program string // the tool's own command name, used to wire up its completions
+}
+
+// environmentCommands returns the commands exposed for the environment's tools and the
+// environment-independent global aliases, renamed or dropped according to the user's alias names
+func environmentCommands(env EnvironmentRecord, globals map[string]string, names AliasNames) []namedCommand {
+	commands := make([]namedCommand, 0, len(env.Tools)+len(globals)+2)
+	hasCLITools := false
+
+	for _, tool := range env.Tools {
+		command := withCredentials(env.Path, pixiRunCommand(env.Path, tool))
+		commands = append(commands, namedCommand{Name: tool.Alias, Command: command, Program: commandProgram(tool.Command)})
+		if tool.Category == categoryCLI {
+			hasCLITools = true
+		}
+	}
+
+	// Route npm and npx through the environment whenever CLI tools live in it
+	if hasCLITools {
+		commands = append(commands, namedCommand{Name: "npx", Command: fmt.Sprintf("pixi run --manifest-path %s npx", env.Path), Program: "npx"})
+		commands = append(commands, namedCommand{Name: "npm", Command: fmt.Sprintf("pixi run --manifest-path %s npm", env.Path), Program: "npm"})
+	}
+
+	// Global aliases such as bat=batcat apply regardless of the active environment
+	globalNames := make([]string, 0, len(globals))
+	for name := range globals {
+		globalNames = append(globalNames, name)
+	}
+	sort.Strings(globalNames)
+	for _, name := range globalNames {
+		commands = append(commands, namedCommand{Name: name, Command: globals[name], Program: commandProgram(globals[name])})
+	}
+
+	exposed := commands[:0]
+	for _, command := range commands {
+		if name, enabled := names.Lookup(command.Name); enabled {
+			command.Name = name
+			exposed = append(exposed, command)
+		}
+	}
+	return exposed
+}
+
+// buildAliasLines returns the managed block lines for the environment in the given shell's syntax:
+// alias definitions in alias mode, or a PATH entry for the shim directory in shim mode
+func buildAliasLines(env EnvironmentRecord, globals map[string]string, names AliasNames, shell, mode string) []string {
+	commands := environmentCommands(env, globals, names)
+	completions := availableCompletions(env.Path, shell)
+
+	if mode == modeShims {
+		lines := []string{formatPathPrepend(shell, shimBinDir(env.Path))}
+		return append(lines, completionLines(env.Path, shell, commands, completions)...)
+	}
+
+	lines := make([]string, 0, len(commands))
+	for _, command := range commands {
+		// zsh expands aliases before completing them, so commands with completions become functions
+		if shell == shellZsh && completions[command.Program] {
+			lines = append(lines, formatWrapperFunction(command.Name, command.Command))
+			continue
+		}
+		lines = append(lines, formatAlias(shell, command.Name, command.Command))
+	}
+	return append(lines, completionLines(env.Path, shell, commands, completions)...)
+}
+
+// commandProgram returns the program a command line runs
+func commandProgram(command string) string {
+	if fields := strings.Fields(command); len(fields) > 0 {
+		return fields[0]
+	}
+	return command
+}
+
+// pixiRunCommand returns the shell command that runs a tool inside its pixi environment
+func pixiRunCommand(envPath string, tool ToolRecord) string {
+	if tool.PixiEnv != "" {
+		return fmt.Sprintf("pixi run --manifest-path %s -e %s %s", envPath, tool.PixiEnv, tool.Command)
+	}
+	return fmt.Sprintf("pixi run --manifest-path %s %s", envPath, tool.Command)
+}
+
+// ActivateEnvironment makes the named environment's aliases the active ones
+// and regenerates the managed block in every selected shell
+func ActivateEnvironment(state *State, name string, progress ProgressCallback) error {
+	env := state.Environment(name)
+	if env == nil {
+		return fmt.Errorf("unknown environment %q", name)
+	}
+
+	targets := make(map[string]bool)
+	for _, shell := range state.Shells() {
+		targets[shell] = true
+	}
+
+	// Drop blocks left in shells that are no longer selected
+	for _, shell := range supportedShells {
+		if !targets[shell] {
+			if rcPath, removed, err := RemoveManagedBlock(shell); err == nil && removed {
+				progress(fmt.Sprintf("✓ Removed ai-menu aliases from %s", displayPath(rcPath)))
+			}
+		}
+	}
+
+	// Shims are always generated so tools can be run by absolute path from scripts
+	binDir, count, err := WriteShims(*env, state.GlobalAliases, state.AliasNames)
+	if err != nil {
+		progress(fmt.Sprintf("⚠️  Could not write shims: %v", err))
+	} else {
+		progress(fmt.Sprintf("✓ Wrote %d shim(s) to %s", count, binDir))
+	}
+
+	// Carry the bat alias of older versions over before their lines are stripped
+	for _, shell := range supportedShells {
+		if rcPath, err := shellRCPath(shell); err == nil {
+			if content, err := os.ReadFile(rcPath); err == nil && hasLegacyBatAlias(string(content)) {
+				state.SetGlobalAlias("bat", "batcat")
+			}
+		}
+	}
+
+	aliasCount := len(environmentCommands(*env, state.GlobalAliases, state.AliasNames))
+	for _, shell := range state.Shells() {
+		lines := buildAliasLines(*env, state.GlobalAliases, state.AliasNames, shell, state.IntegrationMode())
+		rcPath, err := WriteManagedBlock(shell, lines)
+		if err != nil {
+			progress(fmt.Sprintf("⚠️  Could not update %s configuration: %v", shell, err))
+			continue
+		}
+
+		// The managed block replaces the separate alias files of older versions
+		if legacyPath, err := legacyAliasFilePath(shell); err == nil {
+			os.Remove(legacyPath)
+		}
+
+		if state.IntegrationMode() == modeShims {
+			progress(fmt.Sprintf("✓ Added %s to PATH in %s", binDir, displayPath(rcPath)))
+		} else {
+			progress(fmt.Sprintf("✓ Activated %d alias(es) for environment %s in %s", aliasCount, env.Name, displayPath(rcPath)))
+		}
+	}
+
+	state.Active = name
+	return state.Save()
+}
+
+// RemoveShellIntegration deletes the managed block from every supported shell and
+// deactivates the current environment; installed tools are left untouched
+func RemoveShellIntegration(state *State, progress ProgressCallback) error {
+	for _, shell := range supportedShells {
+		rcPath, removed, err := RemoveManagedBlock(shell)
+		if err != nil {
+			progress(fmt.Sprintf("⚠️  Could not clean %s configuration: %v", shell, err))
+			continue
+		}
+		if legacyPath, err := legacyAliasFilePath(shell); err == nil {
+			os.Remove(legacyPath)
+		}
+		if removed {
+			progress(fmt.Sprintf("✓ Removed ai-menu aliases from %s (backup: %s.ai-menu.bak)", displayPath(rcPath), displayPath(rcPath)))
+		}
+	}
+
+	state.Active = ""
+	return state.Save()
+}
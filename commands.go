@@ -0,0 +1,509 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// usage describes the subcommands available besides the interactive menu
+const usage = `Usage:
+  ai-menu                       Start the interactive menu
+  ai-menu list [flags]          List the catalog and what is installed
+  ai-menu install [flags]       Install tools without the menu, e.g. --cli codex,gemini --yes
+  ai-menu uninstall <tool>...   Remove tools from an environment
+  ai-menu status [flags]        Show environments, installed tools and shell integration
+  ai-menu doctor [flags]        Check prerequisites and the environment
+  ai-menu upgrade [flags]       Update the core dependencies and reinstall the recorded tools
+  ai-menu activate [flags]      Print or write project-local activation for an environment
+  ai-menu profiles [flags]      List the presets and saved profiles
+  ai-menu export <format>       Generate a devcontainer, Dockerfile, script or pixi project for a selection
+  ai-menu version               Print the version of this binary
+  ai-menu self-update [flags]   Replace this binary with the latest release
+
+Flags given without a command are passed to install. Most commands accept --json.
+
+Run 'ai-menu <command> -h' for the flags of a command.
+`
+
+// Exit codes of the non-interactive commands
+const (
+	exitOK           = 0
+	exitFailed       = 1 // one or more tools failed to install
+	exitUsage        = 2 // invalid flags or arguments
+	exitCoreFailed   = 3 // the pixi environment could not be prepared
+	exitNotConfirmed = 4 // the installation was not confirmed
+	exitPreflight    = 5 // a prerequisite of the installation is missing
+)
+
+// runCommand executes a non-interactive subcommand and returns the process exit code
+func runCommand(args []string) int {
+	if args[0] == "--version" {
+		return runVersion(args[1:], os.Stdout)
+	}
+
+	// Flags without a subcommand select tools for a non-interactive installation
+	if strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help" {
+		return runInstallFlags(args, os.Stdin, os.Stdout)
+	}
+
+	switch args[0] {
+	case "list":
+		return runList(args[1:], os.Stdout)
+	case "install":
+		return runInstallFlags(args[1:], os.Stdin, os.Stdout)
+	case "uninstall":
+		return runUninstall(args[1:], os.Stdout)
+	case "status":
+		return runStatus(args[1:], os.Stdout)
+	case "doctor":
+		return runDoctor(args[1:], os.Stdout)
+	case "upgrade":
+		return runUpgrade(args[1:], os.Stdin, os.Stdout)
+	case "activate":
+		return runActivate(args[1:], os.Stdout)
+	case "profiles":
+		return runProfiles(args[1:], os.Stdout)
+	case "export":
+		return runExport(args[1:], os.Stdout)
+	case "version":
+		return runVersion(args[1:], os.Stdout)
+	case "self-update":
+		return runSelfUpdate(args[1:], os.Stdin, os.Stdout)
+	case "help":
+		fmt.Print(usage)
+		return exitOK
+	case "-h", "--help":
+		fmt.Print(usage)
+		return exitOK
+	}
+
+	fmt.Fprintf(os.Stderr, "ai-menu: unknown command %q\n\n%s", args[0], usage)
+	return exitUsage
+}
+
+// runActivate prints the eval-able activation snippet, or writes it into a project directory
+func runActivate(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("activate", flag.ContinueOnError)
+	envName := fs.String("env", "", "environment to activate (default: the active environment)")
+	shell := fs.String("shell", "", "shell syntax to print: bash, zsh or fish (default: login shell)")
+	envrcDir := fs.String("envrc", "", "write a direnv .envrc into this project directory")
+	scriptDir := fs.String("script", "", "write ai-menu-activate.sh/.fish into this project directory")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ai-menu activate [flags]")
+		fmt.Fprintln(fs.Output(), "\nPrints a snippet that puts the environment's bin directories on PATH:")
+		fmt.Fprintln(fs.Output(), "  eval \"$(ai-menu activate)\"                 # bash, zsh")
+		fmt.Fprintln(fs.Output(), "  ai-menu activate --shell fish | source     # fish")
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitFailed
+	}
+
+	env, err := resolveEnvironment(state, *envName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitFailed
+	}
+
+	if *envrcDir != "" {
+		path, err := WriteEnvrc(*envrcDir, *env)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ai-menu: could not write %s: %v\n", path, err)
+			return exitFailed
+		}
+		fmt.Fprintf(out, "✓ Wrote %s; run 'direnv allow %s' to enable it\n", path, *envrcDir)
+	}
+
+	if *scriptDir != "" {
+		paths, err := WriteActivationScripts(*scriptDir, *env)
+		for _, path := range paths {
+			fmt.Fprintf(out, "✓ Wrote %s\n", path)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ai-menu: could not write activation scripts: %v\n", err)
+			return exitFailed
+		}
+	}
+
+	if *envrcDir != "" || *scriptDir != "" {
+		return exitOK
+	}
+
+	if *shell == "" {
+		*shell = detectLoginShell()
+		if *shell == "" {
+			*shell = shellBash
+		}
+	}
+	if !containsString(supportedShells, *shell) {
+		fmt.Fprintf(os.Stderr, "ai-menu: unsupported shell %q\n", *shell)
+		return exitUsage
+	}
+
+	fmt.Fprint(out, ActivationSnippet(*shell, *env))
+	return exitOK
+}
+
+// installFlags registers the tool selection flags shared by non-interactive installs
+type installFlags struct {
+	fs                              *flag.FlagSet
+	profile                         *string
+	cli, vscode, special, enhancers *string
+	path, env, shells               *string
+	shims, isolate, saveCredentials *bool
+}
+
+// addInstallFlags defines the selection flags on a flag set, defaulting to the user's configuration
+func addInstallFlags(fs *flag.FlagSet) installFlags {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		currentDir = "."
+	}
+
+	return installFlags{
+		fs:              fs,
+		profile:         fs.String("profile", "", "start from a saved profile or preset, e.g. minimal; other selections are added to it"),
+		cli:             fs.String("cli", "", "comma separated CLI tools, e.g. codex,gemini"),
+		vscode:          fs.String("vscode", "", "comma separated VS Code extensions, e.g. cline,roo"),
+		special:         fs.String("special", "", "comma separated special tools, e.g. jq,rg"),
+		enhancers:       fs.String("enhancers", "", "comma separated CLI tool enhancers, e.g. spec-kit"),
+		path:            fs.String("path", currentDir, "parent directory of the environment"),
+		env:             fs.String("env", defaultEnvName, "environment name"),
+		shells:          fs.String("shell", "", "comma separated shells to configure (default: as last time, or the login shell)"),
+		shims:           fs.Bool("shims", false, "expose tools as shims on PATH instead of aliases"),
+		isolate:         fs.Bool("isolate", false, "give each npm/uv tool its own pixi environment"),
+		saveCredentials: fs.Bool("save-credentials", false, "store API keys the tools need from the current environment"),
+	}
+}
+
+// isSet reports whether a flag was given on the command line
+func (f installFlags) isSet(name string) bool {
+	set := false
+	f.fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// plan resolves the parsed flags into an installation plan
+func (f installFlags) plan(cfg Config, state *State) (InstallPlan, error) {
+	var profile Profile
+	if *f.profile != "" {
+		profiles, err := LoadProfiles()
+		if err != nil {
+			return InstallPlan{}, err
+		}
+		found := false
+		if profile, found = findProfile(profiles, *f.profile); !found {
+			return InstallPlan{}, fmt.Errorf("--profile: unknown profile %q", *f.profile)
+		}
+	}
+
+	profile = profile.withoutDisabled(cfg)
+
+	// The profile's path and environment apply unless given explicitly, then the configured defaults
+	path, envName := *f.path, *f.env
+	if !f.isSet("path") {
+		if profile.InstallPath != "" {
+			path = profile.InstallPath
+		} else if cfg.Defaults.InstallPath != "" {
+			path = cfg.DefaultInstallPath()
+		}
+	}
+	if !f.isSet("env") {
+		if profile.EnvName != "" {
+			envName = profile.EnvName
+		} else {
+			envName = cfg.DefaultEnvName()
+		}
+	}
+
+	// The installers change directory, so the environment path must not be relative
+	installPath, err := filepath.Abs(path)
+	if err != nil {
+		return InstallPlan{}, err
+	}
+
+	plan := InstallPlan{
+		InstallPath:  installPath,
+		EnvName:      envName,
+		Core:         cfg.Core,
+		Isolate:      *f.isolate || cfg.Install.IsolateTools,
+		ShellTargets: state.Shells(),
+		Mode:         state.IntegrationMode(),
+		AliasNames:   state.AliasNames,
+		Registries:   cfg.Registries,
+		Concurrency:  cfg.UI.Concurrency,
+	}
+	if len(cfg.UI.ShellTargets) > 0 {
+		plan.ShellTargets = cfg.UI.ShellTargets
+	}
+	if cfg.UI.Mode != "" {
+		plan.Mode = cfg.UI.Mode
+	}
+	if *f.shims {
+		plan.Mode = modeShims
+	}
+
+	if err := validateEnvName(plan.EnvName); err != nil {
+		return plan, err
+	}
+
+	if *f.shells != "" {
+		plan.ShellTargets = parseCommaList(*f.shells)
+		for _, shell := range plan.ShellTargets {
+			if !containsString(supportedShells, shell) {
+				return plan, fmt.Errorf("unsupported shell %q", shell)
+			}
+		}
+	}
+
+	selections := []struct {
+		flag     string
+		value    string
+		category string
+		catalog  []CatalogEntry
+		target   *[]string
+	}{
+		{"--cli", *f.cli, categoryCLI, cliToolCatalog, &plan.CLITools},
+		{"--vscode", *f.vscode, categoryVSCode, vscodeExtensionCatalog, &plan.VSCodeExtensions},
+		{"--special", *f.special, categorySpecial, specialToolCatalog, &plan.SpecialTools},
+		{"--enhancers", *f.enhancers, categoryEnhancer, cliEnhancerCatalog, &plan.CLIEnhancers},
+	}
+	platform := hostPlatform()
+	profileCLI, profileVSCode, profileSpecial, profileEnhancers, skipped := profile.resolve(platform)
+	if len(skipped) > 0 {
+		return plan, fmt.Errorf("--profile: %s lists items unknown or unavailable on %s: %s", profile.Name, platform, strings.Join(skipped, ", "))
+	}
+	profileItems := [][]string{profileCLI, profileVSCode, profileSpecial, profileEnhancers}
+	for i, selection := range selections {
+		if selection.value != "" && !cfg.CategoryEnabled(selection.category) {
+			return plan, fmt.Errorf("%s: the %s category is disabled in config.toml", selection.flag, selection.category)
+		}
+		*selection.target, err = resolveCatalogItems(selection.catalog, parseCommaList(selection.value))
+		if err != nil {
+			return plan, fmt.Errorf("%s: %v", selection.flag, err)
+		}
+		for _, item := range *selection.target {
+			if entry, _ := lookupCatalogEntry(item); !entry.SupportsPlatform(platform) {
+				return plan, fmt.Errorf("%s: %s is not available on %s", selection.flag, item, platform)
+			}
+		}
+		*selection.target = mergeSelections(selection.catalog, profileItems[i], *selection.target)
+	}
+
+	if *f.saveCredentials {
+		plan.Credentials = make(map[string]string)
+		for _, name := range plan.CredentialVars() {
+			if value := os.Getenv(name); value != "" {
+				plan.Credentials[name] = value
+			}
+		}
+	}
+	return plan, nil
+}
+
+// mergeSelections combines two lists of catalog items without duplicates, in catalog order
+func mergeSelections(catalog []CatalogEntry, a, b []string) []string {
+	merged := []string{}
+	for _, entry := range catalog {
+		if containsString(a, entry.Name) || containsString(b, entry.Name) {
+			merged = append(merged, entry.Name)
+		}
+	}
+	return merged
+}
+
+// printPlan writes a plain-text summary of what an installation will do
+func printPlan(out io.Writer, plan InstallPlan) {
+	fmt.Fprintf(out, "Environment: %s (%s)\n", plan.EnvName, plan.EnvDir())
+	for _, group := range []struct {
+		label string
+		items []string
+	}{
+		{"CLI tools", plan.CLITools},
+		{"VS Code extensions", plan.VSCodeExtensions},
+		{"Special tools", plan.SpecialTools},
+		{"CLI tool enhancers", plan.CLIEnhancers},
+	} {
+		if len(group.items) > 0 {
+			fmt.Fprintf(out, "%s: %s\n", group.label, strings.Join(group.items, ", "))
+		}
+	}
+	fmt.Fprintf(out, "Shells: %s (%s)\n", strings.Join(plan.ShellTargets, ", "), plan.Mode)
+	if plan.Isolate {
+		fmt.Fprintln(out, "Isolation: each npm/uv tool gets its own pixi environment")
+	}
+}
+
+// confirm asks a yes/no question on the given input, treating anything but y/yes as no
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// runInstallFlags installs the tools selected by flags without starting the menu
+func runInstallFlags(args []string, in io.Reader, out io.Writer) int {
+	fs := flag.NewFlagSet("ai-menu", flag.ContinueOnError)
+	selection := addInstallFlags(fs)
+	yes := fs.Bool("yes", false, "install without asking for confirmation")
+	report := addReportFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ai-menu [install] --cli codex,gemini --vscode cline --special jq,rg --enhancers spec-kit --path /workspaces --yes")
+		fmt.Fprintln(fs.Output(), "\nItems can be given by display name, package, alias or short name.")
+		fmt.Fprintln(fs.Output(), "--profile minimal starts from a preset or saved profile; see 'ai-menu profiles'.")
+		fmt.Fprintln(fs.Output(), "Exit codes: 0 success, 1 a tool failed, 2 usage error, 3 environment setup failed, 4 not confirmed, 5 pre-flight check failed.")
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "ai-menu: unexpected argument %q\n", fs.Arg(0))
+		return exitUsage
+	}
+	if err := report.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitUsage
+	}
+	log := report.log(out)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+	}
+	state, err := LoadState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+	}
+
+	plan, err := selection.plan(cfg, state)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitUsage
+	}
+
+	printPlan(log, plan)
+	if !preflightPassed(plan, log) {
+		return exitPreflight
+	}
+	if !*yes && !confirm(in, log, "Proceed with the installation?") {
+		fmt.Fprintln(log, "Installation cancelled")
+		return exitNotConfirmed
+	}
+
+	started := time.Now()
+	results, code := runPlan(plan, log)
+	report.write(out, NewReport(plan.EnvName, plan.EnvDir(), started, results))
+	return code
+}
+
+// reportFlags select where the results of an install, upgrade or removal are reported
+type reportFlags struct {
+	json   *bool
+	path   *string
+	format *string
+}
+
+// addReportFlags defines the report flags on a flag set
+func addReportFlags(fs *flag.FlagSet) reportFlags {
+	return reportFlags{
+		json:   fs.Bool("json", false, "print a JSON report on stdout and progress on stderr"),
+		path:   fs.String("report", "", "write a report of every tool to this file, or - for stdout"),
+		format: fs.String("report-format", "", "report format: json or junit (default: junit for .xml files, otherwise json)"),
+	}
+}
+
+// validate checks the report format before anything is installed
+func (f reportFlags) validate() error {
+	_, err := reportFormat(*f.path, *f.format)
+	return err
+}
+
+// log returns where progress is printed, keeping stdout clean when the report goes there
+func (f reportFlags) log(out io.Writer) io.Writer {
+	if *f.json || *f.path == "-" {
+		return os.Stderr
+	}
+	return out
+}
+
+// write emits the report as requested by the flags
+func (f reportFlags) write(out io.Writer, report Report) {
+	if *f.json {
+		if err := WriteReport("-", reportJSON, report, out); err != nil {
+			fmt.Fprintf(os.Stderr, "ai-menu: could not write report: %v\n", err)
+		}
+	}
+	if *f.path != "" {
+		if err := WriteReport(*f.path, *f.format, report, out); err != nil {
+			fmt.Fprintf(os.Stderr, "ai-menu: could not write report: %v\n", err)
+		}
+	}
+}
+
+// preflightPassed prints the pre-flight checks that did not pass and reports whether none failed
+func preflightPassed(plan InstallPlan, out io.Writer) bool {
+	checks := Preflight(plan)
+	for _, check := range unpassedChecks(checks) {
+		fmt.Fprintln(out, formatCheck(check))
+	}
+	if !doctorPassed(checks) {
+		fmt.Fprintln(out, "Pre-flight check failed; nothing was installed")
+		return false
+	}
+	return true
+}
+
+// runPlan runs an installation with line-oriented progress and returns the results and exit code
+func runPlan(plan InstallPlan, out io.Writer) ([]InstallResult, int) {
+	results, err := RunInstallation(plan, func(msg string) { fmt.Fprintln(out, msg) })
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		if errors.Is(err, errCoreDependencies) {
+			return results, exitCoreFailed
+		}
+		return results, exitFailed
+	}
+	return results, summarizeResults(results, "installed", out)
+}
+
+// summarizeResults prints the success and failure counts and returns the matching exit code
+func summarizeResults(results []InstallResult, verb string, out io.Writer) int {
+	failed := 0
+	for _, result := range results {
+		if !result.Success {
+			failed++
+		}
+	}
+	fmt.Fprintf(out, "%d %s, %d failed\n", len(results)-failed, verb, failed)
+	if failed > 0 {
+		return exitFailed
+	}
+	return exitOK
+}
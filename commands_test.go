@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestInstallFlagsPlan(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	profile := Profile{Name: "team", CLITools: []string{"codex"}, SpecialTools: []string{"modal"}, InstallPath: "/projects", EnvName: "team-env"}
+	if err := SaveProfile(profile); err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		codex  = "Codex by OpenAI"
+		gemini = "Gemini CLI by Google"
+		modal  = "modal - Serverless cloud platform CLI"
+		cline  = "saoudrizwan.claude-dev - Cline"
+	)
+	// want holds the parts of the plan each case checks
+	type want struct {
+		path, env string
+		cli       []string
+		vscode    []string
+		special   []string
+		isolate   bool
+		mode      string
+	}
+	tests := []struct {
+		name      string
+		args      []string
+		configure func(*Config)
+		want      want
+		wantErr   bool
+	}{
+		{"catalog names", []string{"--path", "/work", "--cli", "gemini,CODEX", "--vscode", "cline", "--special", "modal"}, nil,
+			want{"/work", defaultEnvName, []string{codex, gemini}, []string{cline}, []string{modal}, false, modeAliases}, false},
+		{"full display name", []string{"--path", "/work", "--cli", codex}, nil,
+			want{"/work", defaultEnvName, []string{codex}, nil, nil, false, modeAliases}, false},
+		{"unknown item", []string{"--cli", "codex,nope"}, nil, want{}, true},
+		// Profile items are merged with the flags in catalog order
+		{"profile", []string{"--profile", "TEAM", "--cli", "gemini"}, nil,
+			want{"/projects", "team-env", []string{codex, gemini}, nil, []string{modal}, false, modeAliases}, false},
+		{"flags override the profile", []string{"--profile", "team", "--path", "/work", "--env", "mine"}, nil,
+			want{"/work", "mine", []string{codex}, nil, []string{modal}, false, modeAliases}, false},
+		{"unknown profile", []string{"--profile", "nope"}, nil, want{}, true},
+		{"configured defaults", []string{"--cli", "codex"}, func(cfg *Config) {
+			cfg.Defaults.InstallPath = "/configured"
+			cfg.Defaults.EnvName = "cfg-env"
+			cfg.Install.IsolateTools = true
+			cfg.UI.Mode = modeShims
+		}, want{"/configured", "cfg-env", []string{codex}, nil, nil, true, modeShims}, false},
+		{"profile before configured defaults", []string{"--profile", "team"}, func(cfg *Config) {
+			cfg.Defaults.InstallPath = "/configured"
+			cfg.Defaults.EnvName = "cfg-env"
+		}, want{"/projects", "team-env", []string{codex}, nil, []string{modal}, false, modeAliases}, false},
+		{"disabled category", []string{"--cli", "codex"}, func(cfg *Config) {
+			cfg.Defaults.DisabledCategories = []string{categoryCLI}
+		}, want{}, true},
+		{"disabled category in a profile", []string{"--profile", "team", "--path", "/work"}, func(cfg *Config) {
+			cfg.Defaults.DisabledCategories = []string{categoryCLI}
+		}, want{"/work", "team-env", nil, nil, []string{modal}, false, modeAliases}, false},
+		{"shims and isolation", []string{"--path", "/work", "--shims", "--isolate"}, nil,
+			want{"/work", defaultEnvName, nil, nil, nil, true, modeShims}, false},
+		{"invalid environment name", []string{"--env", "a/b"}, nil, want{}, true},
+		{"unsupported shell", []string{"--shell", "tcsh"}, nil, want{}, true},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("install", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		f := addInstallFlags(fs)
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		cfg := defaultConfig()
+		if tt.configure != nil {
+			tt.configure(&cfg)
+		}
+
+		plan, err := f.plan(cfg, &State{})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		got := want{plan.InstallPath, plan.EnvName, plan.CLITools, plan.VSCodeExtensions, plan.SpecialTools, plan.Isolate, plan.Mode}
+		for _, list := range []*[]string{&got.cli, &got.vscode, &got.special} {
+			if len(*list) == 0 {
+				*list = nil
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: plan = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
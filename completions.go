@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// completionDir returns where the generated completion scripts of an environment are stored
+func completionDir(envPath, shell string) string {
+	return filepath.Join(envPath, "share", "completions", shell)
+}
+
+// completionFileName returns the conventional file name of a program's completion script
+func completionFileName(program, shell string) string {
+	switch shell {
+	case shellZsh:
+		return "_" + program
+	case shellFish:
+		return program + ".fish"
+	}
+	return program
+}
+
+// completionCommand returns the catalog completion command for the given shell
+func completionCommand(template, shell string) string {
+	return strings.ReplaceAll(template, "{shell}", shell)
+}
+
+// completionJob is a completion script to generate
+type completionJob struct {
+	Program string // program the script completes
+	Command string // shell command that prints the script
+}
+
+// completionJobs returns the completion scripts to generate for the environment's tools and
+// the installed special tools whose catalog entries declare a completion command
+func completionJobs(env EnvironmentRecord, shell string) []completionJob {
+	jobs := []completionJob{}
+
+	for _, tool := range env.Tools {
+		entry, ok := lookupCatalogPackage(tool.Name)
+		if !ok || entry.Completion == "" {
+			continue
+		}
+		// Run the tool inside its pixi environment, as the aliases and shims do
+		command := completionCommand(entry.Completion, shell)
+		program := commandProgram(command)
+		tool.Command = command
+		jobs = append(jobs, completionJob{Program: program, Command: pixiRunCommand(env.Path, tool)})
+	}
+
+	// Special tools are installed system-wide and run directly
+	for _, entry := range specialToolCatalog {
+		if entry.Completion == "" {
+			continue
+		}
+		command := completionCommand(entry.Completion, shell)
+		if _, err := exec.LookPath(commandProgram(command)); err == nil {
+			jobs = append(jobs, completionJob{Program: commandProgram(command), Command: command})
+		}
+	}
+	return jobs
+}
+
+// GenerateCompletions regenerates the completion scripts of the environment for each shell
+func GenerateCompletions(env EnvironmentRecord, shells []string, progress ProgressCallback) {
+	for _, shell := range shells {
+		dir := completionDir(env.Path, shell)
+		os.RemoveAll(dir)
+
+		jobs := completionJobs(env, shell)
+		if len(jobs) == 0 {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			progress(fmt.Sprintf("⚠️  Could not create %s: %v", dir, err))
+			continue
+		}
+
+		generated := []string{}
+		for _, job := range jobs {
+			var stdout, stderr bytes.Buffer
+			cmd := exec.Command("sh", "-c", job.Command)
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			if err := cmd.Run(); err != nil {
+				progress(fmt.Sprintf("⚠️  Could not generate %s completions for %s: %v", shell, job.Program, err))
+				continue
+			}
+			if stdout.Len() == 0 {
+				progress(fmt.Sprintf("⚠️  %s printed no %s completions", job.Program, shell))
+				continue
+			}
+
+			path := filepath.Join(dir, completionFileName(job.Program, shell))
+			if err := os.WriteFile(path, stdout.Bytes(), 0644); err != nil {
+				progress(fmt.Sprintf("⚠️  Could not write %s: %v", path, err))
+				continue
+			}
+			generated = append(generated, job.Program)
+		}
+
+		if len(generated) > 0 {
+			progress(fmt.Sprintf("✓ Generated %s completions for %s", shell, strings.Join(generated, ", ")))
+		}
+	}
+}
+
+// availableCompletions returns the programs with a generated completion script for the shell
+func availableCompletions(envPath, shell string) map[string]bool {
+	available := make(map[string]bool)
+	entries, err := os.ReadDir(completionDir(envPath, shell))
+	if err != nil {
+		return available
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		switch shell {
+		case shellZsh:
+			name = strings.TrimPrefix(name, "_")
+		case shellFish:
+			name = strings.TrimSuffix(name, ".fish")
+		}
+		available[name] = true
+	}
+	return available
+}
+
+// completionLines returns the managed block lines that load the generated completion scripts
+// and register them for aliases whose name differs from the program they run
+func completionLines(envPath, shell string, commands []namedCommand, available map[string]bool) []string {
+	if len(available) == 0 {
+		return nil
+	}
+
+	programs := make([]string, 0, len(available))
+	for program := range available {
+		programs = append(programs, program)
+	}
+	sort.Strings(programs)
+
+	lines := []string{}
+	if shell == shellZsh {
+		// Completion scripts register themselves with compdef, which needs compinit
+		lines = append(lines, "(( $+functions[compdef] )) || { autoload -Uz compinit && compinit -i; }")
+	}
+	for _, program := range programs {
+		path := filepath.Join(completionDir(envPath, shell), completionFileName(program, shell))
+		if shell == shellFish {
+			lines = append(lines, fmt.Sprintf("test -f %q; and source %q", path, path))
+		} else {
+			lines = append(lines, fmt.Sprintf("[ -f %q ] && source %q", path, path))
+		}
+	}
+
+	for _, command := range commands {
+		if command.Name == command.Program || !available[command.Program] {
+			continue
+		}
+		switch shell {
+		case shellBash:
+			lines = append(lines, fmt.Sprintf("complete -p %s >/dev/null 2>&1 && eval \"$(complete -p %s) %s\"", command.Program, command.Program, command.Name))
+		case shellZsh:
+			lines = append(lines, fmt.Sprintf("compdef %s=%s", command.Name, command.Program))
+		case shellFish:
+			lines = append(lines, fmt.Sprintf("complete -c %s --wraps %s", command.Name, command.Program))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompletionLines(t *testing.T) {
+	commands := []namedCommand{
+		{Name: "codex", Command: "pixi run codex", Program: "codex"},
+		{Name: "cc", Command: "pixi run claude", Program: "claude"},
+		{Name: "g", Command: "pixi run gemini", Program: "gemini"}, // no completion script
+	}
+	available := map[string]bool{"codex": true, "claude": true}
+
+	tests := []struct {
+		shell     string
+		available map[string]bool
+		want      []string
+	}{
+		{shellBash, nil, nil},
+		{shellBash, available, []string{
+			`[ -f "/env/share/completions/bash/claude" ] && source "/env/share/completions/bash/claude"`,
+			`[ -f "/env/share/completions/bash/codex" ] && source "/env/share/completions/bash/codex"`,
+			`complete -p claude >/dev/null 2>&1 && eval "$(complete -p claude) cc"`,
+		}},
+		{shellZsh, available, []string{
+			"(( $+functions[compdef] )) || { autoload -Uz compinit && compinit -i; }",
+			`[ -f "/env/share/completions/zsh/_claude" ] && source "/env/share/completions/zsh/_claude"`,
+			`[ -f "/env/share/completions/zsh/_codex" ] && source "/env/share/completions/zsh/_codex"`,
+			"compdef cc=claude",
+		}},
+		{shellFish, available, []string{
+			`test -f "/env/share/completions/fish/claude.fish"; and source "/env/share/completions/fish/claude.fish"`,
+			`test -f "/env/share/completions/fish/codex.fish"; and source "/env/share/completions/fish/codex.fish"`,
+			"complete -c cc --wraps claude",
+		}},
+	}
+	for _, tt := range tests {
+		if got := completionLines("/env", tt.shell, commands, tt.available); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("completionLines(%s) = %q, want %q", tt.shell, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config holds the user settings stored in config.toml
+type Config struct {
+	Core       CoreConfig     `toml:"core"`
+	Install    InstallConfig  `toml:"install"`
+	Defaults   DefaultsConfig `toml:"defaults"`
+	Registries RegistryConfig `toml:"registries"`
+	UI         UIConfig       `toml:"ui"`
+}
+
+// CoreConfig describes the core dependencies that are always installed in the pixi environment
+type CoreConfig struct {
+	NodeVersion   string   `toml:"node_version"`
+	PythonVersion string   `toml:"python_version"`
+	ExtraPackages []string `toml:"extra_packages"`
+	Platforms     []string `toml:"platforms"`
+}
+
+// InstallConfig controls how tools are laid out inside the environment
+type InstallConfig struct {
+	// IsolateTools gives every npm/uv tool and enhancer its own pixi feature and environment
+	IsolateTools bool `toml:"isolate_tools"`
+}
+
+// DefaultsConfig preloads the menu and the install flags
+type DefaultsConfig struct {
+	InstallPath string `toml:"install_path,omitempty"` // parent directory of the environment, ~ is expanded
+	EnvName     string `toml:"env,omitempty"`
+	// Preselected items, given like command-line names, e.g. codex or rg
+	CLITools         []string `toml:"cli,omitempty"`
+	VSCodeExtensions []string `toml:"vscode,omitempty"`
+	SpecialTools     []string `toml:"special,omitempty"`
+	CLIEnhancers     []string `toml:"enhancers,omitempty"`
+	// DisabledCategories hides selection screens and rejects their flags: cli, vscode, special or enhancer
+	DisabledCategories []string `toml:"disabled_categories,omitempty"`
+}
+
+// RegistryConfig points the package managers at mirrors instead of the public registries
+type RegistryConfig struct {
+	Npm  string `toml:"npm,omitempty"`  // npm registry URL
+	PyPI string `toml:"pypi,omitempty"` // Python package index URL used by uv
+}
+
+// UIConfig holds the interface preferences
+type UIConfig struct {
+	ShellTargets []string `toml:"shells,omitempty"` // shells that get the aliases; default: as last time
+	Mode         string   `toml:"mode,omitempty"`   // aliases or shims; default: as last time
+	Concurrency  int      `toml:"concurrency,omitempty"`
+	Theme        string   `toml:"theme,omitempty"`
+}
+
+// Env returns the environment variables that select the registries, as NAME=value
+func (r RegistryConfig) Env() []string {
+	env := []string{}
+	if r.Npm != "" {
+		env = append(env, "NPM_CONFIG_REGISTRY="+r.Npm)
+	}
+	if r.PyPI != "" {
+		env = append(env, "UV_DEFAULT_INDEX="+r.PyPI)
+	}
+	return env
+}
+
+// defaultConcurrency is how many installed tools are checked for their version at once
+const defaultConcurrency = 4
+
+// configCategories are the categories that can be disabled
+var configCategories = []string{categoryCLI, categoryVSCode, categorySpecial, categoryEnhancer}
+
+// CoreDependency is a single conda-forge package added to the pixi environment
+type CoreDependency struct {
+	Name    string
+	Version string
+}
+
+// defaultConfig returns the settings used when no config file exists
+func defaultConfig() Config {
+	return Config{
+		Core: CoreConfig{
+			NodeVersion:   "22.*",
+			PythonVersion: "3.12.*",
+			ExtraPackages: []string{},
+			Platforms:     defaultPlatforms(),
+		},
+		UI: UIConfig{
+			Concurrency: defaultConcurrency,
+			Theme:       themeDefault,
+		},
+	}
+}
+
+// configDir returns the ai-menu configuration directory, honouring XDG_CONFIG_HOME
+func configDir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "ai-menu"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "ai-menu"), nil
+}
+
+// configPath returns the location of config.toml
+func configPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// LoadConfig reads config.toml, falling back to defaults for missing keys, and applies the
+// AI_MENU_* environment variables on top
+func LoadConfig() (Config, error) {
+	cfg, err := loadConfigFile()
+	return cfg, errors.Join(err, applyEnvOverrides(&cfg), cfg.validate())
+}
+
+// loadConfigFile reads config.toml without the environment overrides, as it is saved
+func loadConfigFile() (Config, error) {
+	cfg := defaultConfig()
+
+	path, err := configPath()
+	if err != nil {
+		return cfg, err
+	}
+
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return defaultConfig(), fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	cfg.Core = cfg.Core.normalized()
+	return cfg, nil
+}
+
+// applyEnvOverrides replaces settings with the AI_MENU_* environment variables that are set.
+// Lists are comma separated.
+func applyEnvOverrides(cfg *Config) error {
+	values := []struct {
+		name   string
+		target *string
+	}{
+		{"AI_MENU_INSTALL_PATH", &cfg.Defaults.InstallPath},
+		{"AI_MENU_DEFAULT_ENV", &cfg.Defaults.EnvName},
+		{"AI_MENU_NPM_REGISTRY", &cfg.Registries.Npm},
+		{"AI_MENU_PYPI_INDEX", &cfg.Registries.PyPI},
+		{"AI_MENU_MODE", &cfg.UI.Mode},
+		{"AI_MENU_THEME", &cfg.UI.Theme},
+	}
+	for _, override := range values {
+		if value, ok := os.LookupEnv(override.name); ok {
+			*override.target = strings.TrimSpace(value)
+		}
+	}
+
+	lists := []struct {
+		name   string
+		target *[]string
+	}{
+		{"AI_MENU_CLI", &cfg.Defaults.CLITools},
+		{"AI_MENU_VSCODE", &cfg.Defaults.VSCodeExtensions},
+		{"AI_MENU_SPECIAL", &cfg.Defaults.SpecialTools},
+		{"AI_MENU_ENHANCERS", &cfg.Defaults.CLIEnhancers},
+		{"AI_MENU_DISABLED_CATEGORIES", &cfg.Defaults.DisabledCategories},
+		{"AI_MENU_SHELLS", &cfg.UI.ShellTargets},
+	}
+	for _, override := range lists {
+		if value, ok := os.LookupEnv(override.name); ok {
+			*override.target = parseCommaList(value)
+		}
+	}
+
+	var errs []error
+	if value, ok := os.LookupEnv("AI_MENU_ISOLATE_TOOLS"); ok {
+		if isolate, err := strconv.ParseBool(strings.TrimSpace(value)); err == nil {
+			cfg.Install.IsolateTools = isolate
+		} else {
+			errs = append(errs, fmt.Errorf("AI_MENU_ISOLATE_TOOLS: invalid value %q", value))
+		}
+	}
+	if value, ok := os.LookupEnv("AI_MENU_CONCURRENCY"); ok {
+		if concurrency, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			cfg.UI.Concurrency = concurrency
+		} else {
+			errs = append(errs, fmt.Errorf("AI_MENU_CONCURRENCY: invalid value %q", value))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validate resets the settings that cannot be used to their defaults and reports them
+func (c *Config) validate() error {
+	defaults := defaultConfig()
+	var errs []error
+
+	for _, category := range c.Defaults.DisabledCategories {
+		if !containsString(configCategories, category) {
+			errs = append(errs, fmt.Errorf("unknown category %q in disabled_categories (want %s)", category, strings.Join(configCategories, ", ")))
+		}
+	}
+	for _, shell := range c.UI.ShellTargets {
+		if !containsString(supportedShells, shell) {
+			errs = append(errs, fmt.Errorf("unsupported shell %q in ui.shells", shell))
+			c.UI.ShellTargets = nil
+			break
+		}
+	}
+	if c.UI.Mode != "" && c.UI.Mode != modeAliases && c.UI.Mode != modeShims {
+		errs = append(errs, fmt.Errorf("unknown ui.mode %q (want %s or %s)", c.UI.Mode, modeAliases, modeShims))
+		c.UI.Mode = ""
+	}
+	if c.UI.Concurrency < 1 {
+		errs = append(errs, fmt.Errorf("ui.concurrency must be at least 1"))
+		c.UI.Concurrency = defaults.UI.Concurrency
+	}
+	if !containsString(themeNames, c.UI.Theme) {
+		errs = append(errs, fmt.Errorf("unknown ui.theme %q (want %s)", c.UI.Theme, strings.Join(themeNames, ", ")))
+		c.UI.Theme = defaults.UI.Theme
+	}
+	if c.Defaults.EnvName != "" {
+		if err := validateEnvName(c.Defaults.EnvName); err != nil {
+			errs = append(errs, fmt.Errorf("defaults.env: %w", err))
+			c.Defaults.EnvName = ""
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CategoryEnabled reports whether a category's screen and flags are available
+func (c Config) CategoryEnabled(category string) bool {
+	return !containsString(c.Defaults.DisabledCategories, category)
+}
+
+// DefaultInstallPath returns the configured parent directory of new environments, or the
+// working directory
+func (c Config) DefaultInstallPath() string {
+	if path := expandHome(c.Defaults.InstallPath); path != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs
+		}
+	}
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	return currentDir
+}
+
+// DefaultEnvName returns the configured environment name, or the default one
+func (c Config) DefaultEnvName() string {
+	if c.Defaults.EnvName != "" {
+		return c.Defaults.EnvName
+	}
+	return defaultEnvName
+}
+
+// Preselection returns the configured preselected items as a profile, so they resolve like one
+func (c Config) Preselection() Profile {
+	return Profile{
+		Name:             "defaults",
+		CLITools:         c.Defaults.CLITools,
+		VSCodeExtensions: c.Defaults.VSCodeExtensions,
+		SpecialTools:     c.Defaults.SpecialTools,
+		CLIEnhancers:     c.Defaults.CLIEnhancers,
+	}.withoutDisabled(c)
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+}
+
+// SaveConfig writes the configuration to config.toml
+func SaveConfig(cfg Config) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Write a temporary file and rename it over the config, so an interrupted save never
+	// leaves a truncated config.toml behind
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config.toml-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if err := toml.NewEncoder(tmp).Encode(cfg); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// normalized fills in empty versions and platforms and trims the list entries
+func (c CoreConfig) normalized() CoreConfig {
+	defaults := defaultConfig().Core
+	if strings.TrimSpace(c.NodeVersion) == "" {
+		c.NodeVersion = defaults.NodeVersion
+	}
+	if strings.TrimSpace(c.PythonVersion) == "" {
+		c.PythonVersion = defaults.PythonVersion
+	}
+	c.NodeVersion = strings.TrimSpace(c.NodeVersion)
+	c.PythonVersion = strings.TrimSpace(c.PythonVersion)
+
+	extras := make([]string, 0, len(c.ExtraPackages))
+	for _, pkg := range c.ExtraPackages {
+		if pkg = strings.TrimSpace(pkg); pkg != "" {
+			extras = append(extras, pkg)
+		}
+	}
+	c.ExtraPackages = extras
+
+	platforms := make([]string, 0, len(c.Platforms))
+	for _, platform := range c.Platforms {
+		if platform = strings.TrimSpace(platform); platform != "" {
+			platforms = append(platforms, platform)
+		}
+	}
+	if len(platforms) == 0 {
+		platforms = defaults.Platforms
+	}
+	c.Platforms = platforms
+	return c
+}
+
+// Dependencies returns the full list of core packages in installation order
+func (c CoreConfig) Dependencies() []CoreDependency {
+	deps := []CoreDependency{
+		{Name: "nodejs", Version: c.NodeVersion},
+		{Name: "python", Version: c.PythonVersion},
+		{Name: "uv"},
+	}
+	for _, spec := range c.ExtraPackages {
+		deps = append(deps, parseCondaSpec(spec))
+	}
+	return deps
+}
+
+// parseCondaSpec splits a spec such as "git" or "make=4.*" into name and version. The single
+// "=" of a fuzzy match is dropped; other operators, including the exact "==", are kept.
+func parseCondaSpec(spec string) CoreDependency {
+	spec = strings.TrimSpace(spec)
+	idx := strings.IndexAny(spec, "=<>!~ ")
+	if idx < 0 {
+		return CoreDependency{Name: spec}
+	}
+	version := strings.Join(strings.Fields(spec[idx:]), "")
+	if strings.HasPrefix(version, "=") && !strings.HasPrefix(version, "==") {
+		version = version[1:]
+	}
+	return CoreDependency{Name: spec[:idx], Version: version}
+}
+
+// Spec returns the argument passed to pixi add
+func (d CoreDependency) Spec() string {
+	if d.Version == "" {
+		return d.Name
+	}
+	if strings.ContainsAny(d.Version[:1], "=<>!~") {
+		return d.Name + d.Version
+	}
+	return d.Name + "=" + d.Version
+}
+
+// Label returns a human readable description such as "Node.js 22.*"
+func (d CoreDependency) Label() string {
+	name := d.Name
+	switch d.Name {
+	case "nodejs":
+		name = "Node.js"
+	case "python":
+		name = "Python"
+	}
+	if d.Version == "" {
+		return name
+	}
+	return name + " " + d.Version
+}
+
+// parseCommaList splits a comma separated list entered in the core configuration screen
+func parseCommaList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// configEnvVars lists every variable applyEnvOverrides reads
+var configEnvVars = []string{
+	"AI_MENU_INSTALL_PATH", "AI_MENU_DEFAULT_ENV", "AI_MENU_NPM_REGISTRY", "AI_MENU_PYPI_INDEX",
+	"AI_MENU_MODE", "AI_MENU_THEME", "AI_MENU_CLI", "AI_MENU_VSCODE", "AI_MENU_SPECIAL",
+	"AI_MENU_ENHANCERS", "AI_MENU_DISABLED_CATEGORIES", "AI_MENU_SHELLS", "AI_MENU_ISOLATE_TOOLS",
+	"AI_MENU_CONCURRENCY",
+}
+
+func TestParseCondaSpec(t *testing.T) {
+	tests := []struct {
+		spec     string
+		want     CoreDependency
+		wantSpec string
+	}{
+		{"git", CoreDependency{Name: "git"}, "git"},
+		{"  git  ", CoreDependency{Name: "git"}, "git"},
+		{"make=4.*", CoreDependency{Name: "make", Version: "4.*"}, "make=4.*"},
+		// An exact pin stays exact; make=4.4 would also match 4.4.1
+		{"make==4.4", CoreDependency{Name: "make", Version: "==4.4"}, "make==4.4"},
+		{"make == 4.4", CoreDependency{Name: "make", Version: "==4.4"}, "make==4.4"},
+		{"cmake>=3.20", CoreDependency{Name: "cmake", Version: ">=3.20"}, "cmake>=3.20"},
+		{"ripgrep <14", CoreDependency{Name: "ripgrep", Version: "<14"}, "ripgrep<14"},
+		{"go ~=1.22", CoreDependency{Name: "go", Version: "~=1.22"}, "go~=1.22"},
+		{"python 3.12.*", CoreDependency{Name: "python", Version: "3.12.*"}, "python=3.12.*"},
+		{"", CoreDependency{}, ""},
+	}
+	for _, tt := range tests {
+		got := parseCondaSpec(tt.spec)
+		if got != tt.want {
+			t.Errorf("parseCondaSpec(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+		if spec := got.Spec(); spec != tt.wantSpec {
+			t.Errorf("parseCondaSpec(%q).Spec() = %q, want %q", tt.spec, spec, tt.wantSpec)
+		}
+	}
+}
+
+func TestSaveConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	cfg := defaultConfig()
+	cfg.Core.ExtraPackages = []string{"git", "make=4.*"}
+	for _, nodeVersion := range []string{"20.*", "22.*"} {
+		cfg.Core.NodeVersion = nodeVersion
+		if err := SaveConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := loadConfigFile()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(loaded.Core, cfg.Core) {
+			t.Errorf("loaded %+v, want %+v", loaded.Core, cfg.Core)
+		}
+	}
+
+	// Only config.toml is left; no temporary file
+	entries, err := os.ReadDir(filepath.Join(dir, "ai-menu"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "config.toml" {
+		t.Errorf("config directory contains %v, want only config.toml", entries)
+	}
+	info, err := os.Stat(filepath.Join(dir, "ai-menu", "config.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("config.toml mode = %v, want 0644", info.Mode().Perm())
+	}
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    func(*Config)
+		wantErr bool
+	}{
+		{"nothing set", nil, func(*Config) {}, false},
+		{"strings", map[string]string{
+			"AI_MENU_INSTALL_PATH": " ~/work ",
+			"AI_MENU_DEFAULT_ENV":  "team",
+			"AI_MENU_NPM_REGISTRY": "https://npm.example.com",
+			"AI_MENU_MODE":         "shims",
+		}, func(cfg *Config) {
+			cfg.Defaults.InstallPath = "~/work"
+			cfg.Defaults.EnvName = "team"
+			cfg.Registries.Npm = "https://npm.example.com"
+			cfg.UI.Mode = modeShims
+		}, false},
+		{"lists", map[string]string{
+			"AI_MENU_CLI":    "codex, gemini,,",
+			"AI_MENU_SHELLS": "bash,fish",
+		}, func(cfg *Config) {
+			cfg.Defaults.CLITools = []string{"codex", "gemini"}
+			cfg.UI.ShellTargets = []string{shellBash, shellFish}
+		}, false},
+		// An empty variable clears the setting
+		{"empty list", map[string]string{"AI_MENU_SPECIAL": ""}, func(cfg *Config) {
+			cfg.Defaults.SpecialTools = []string{}
+		}, false},
+		{"numbers and booleans", map[string]string{"AI_MENU_ISOLATE_TOOLS": "true", "AI_MENU_CONCURRENCY": " 8 "}, func(cfg *Config) {
+			cfg.Install.IsolateTools = true
+			cfg.UI.Concurrency = 8
+		}, false},
+		{"invalid values are reported and ignored", map[string]string{"AI_MENU_ISOLATE_TOOLS": "sometimes", "AI_MENU_CONCURRENCY": "many"}, func(*Config) {}, true},
+	}
+	for _, tt := range tests {
+		for _, name := range configEnvVars {
+			t.Setenv(name, "")
+			os.Unsetenv(name)
+		}
+		for name, value := range tt.env {
+			t.Setenv(name, value)
+		}
+
+		cfg := defaultConfig()
+		cfg.Defaults.SpecialTools = []string{"jq"}
+		want := defaultConfig()
+		want.Defaults.SpecialTools = []string{"jq"}
+		tt.want(&want)
+
+		err := applyEnvOverrides(&cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if !reflect.DeepEqual(cfg, want) {
+			t.Errorf("%s: config %+v, want %+v", tt.name, cfg, want)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(*Config)
+		want    func(*Config) // the config after invalid settings are reset
+		wantErr bool
+	}{
+		{"defaults", func(*Config) {}, func(*Config) {}, false},
+		{"valid settings", func(cfg *Config) {
+			cfg.Defaults.DisabledCategories = []string{categoryVSCode}
+			cfg.UI.ShellTargets = []string{shellZsh}
+			cfg.UI.Mode = modeShims
+			cfg.UI.Theme = themeMono
+		}, nil, false},
+		// Unknown categories are ignored, so they are kept
+		{"unknown category", func(cfg *Config) { cfg.Defaults.DisabledCategories = []string{"games"} }, nil, true},
+		{"unsupported shell", func(cfg *Config) { cfg.UI.ShellTargets = []string{shellBash, "tcsh"} }, func(cfg *Config) { cfg.UI.ShellTargets = nil }, true},
+		{"unknown mode", func(cfg *Config) { cfg.UI.Mode = "links" }, func(cfg *Config) { cfg.UI.Mode = "" }, true},
+		{"concurrency", func(cfg *Config) { cfg.UI.Concurrency = 0 }, func(*Config) {}, true},
+		{"unknown theme", func(cfg *Config) { cfg.UI.Theme = "neon" }, func(*Config) {}, true},
+		{"invalid environment name", func(cfg *Config) { cfg.Defaults.EnvName = "a/b" }, func(cfg *Config) { cfg.Defaults.EnvName = "" }, true},
+	}
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		tt.change(&cfg)
+		// want starts from the defaults, or from the change when nothing is reset
+		want := defaultConfig()
+		if tt.want != nil {
+			tt.want(&want)
+		} else {
+			tt.change(&want)
+		}
+
+		err := cfg.validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if !reflect.DeepEqual(cfg, want) {
+			t.Errorf("%s: config %+v, want %+v", tt.name, cfg, want)
+		}
+	}
+}
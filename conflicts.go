@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Patterns that define a command in bash/zsh and fish startup files
+var (
+	posixAliasDef    = regexp.MustCompile(`^\s*alias\s+(?:-g\s+)?([A-Za-z0-9._+-]+)=`)
+	posixFunctionDef = regexp.MustCompile(`^\s*(?:function\s+)?([A-Za-z0-9._+-]+)\s*\(\)`)
+	fishAliasDef     = regexp.MustCompile(`^\s*(?:alias|abbr\s+(?:-a|--add))\s+([A-Za-z0-9._+-]+)[\s=]`)
+	fishFunctionDef  = regexp.MustCompile(`^\s*function\s+([A-Za-z0-9._+-]+)`)
+)
+
+// validAliasName matches names that are safe to use as an alias, fish function and shim file name
+var validAliasName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]*$`)
+
+// shellDefinitionFiles returns the startup files in which the user may have defined aliases or functions
+func shellDefinitionFiles(shell string) []string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	switch shell {
+	case shellBash:
+		return []string{
+			filepath.Join(homeDir, ".bashrc"),
+			filepath.Join(homeDir, ".bash_profile"),
+			filepath.Join(homeDir, ".bash_aliases"),
+		}
+	case shellZsh:
+		rcPath, _ := shellRCPath(shellZsh)
+		return []string{rcPath, filepath.Join(filepath.Dir(rcPath), ".zsh_aliases")}
+	case shellFish:
+		rcPath, _ := shellRCPath(shellFish)
+		fishDir := filepath.Dir(filepath.Dir(rcPath))
+		files := []string{filepath.Join(fishDir, "config.fish")}
+		if matches, err := filepath.Glob(filepath.Join(filepath.Dir(rcPath), "*.fish")); err == nil {
+			for _, match := range matches {
+				// ai-menu's own file only contains the definitions being replaced
+				if match != rcPath {
+					files = append(files, match)
+				}
+			}
+		}
+		return files
+	}
+	return nil
+}
+
+// shellDefinitions returns the aliases and functions defined in a shell's startup files,
+// mapped to a description of where each one is defined. ai-menu's managed block is ignored.
+func shellDefinitions(shell string) map[string]string {
+	definitions := make(map[string]string)
+	aliasDef, functionDef := posixAliasDef, posixFunctionDef
+	if shell == shellFish {
+		aliasDef, functionDef = fishAliasDef, fishFunctionDef
+	}
+	patterns := map[string]*regexp.Regexp{"alias": aliasDef, "function": functionDef}
+
+	for _, path := range shellDefinitionFiles(shell) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(stripManagedBlock(string(content)), "\n") {
+			for kind, pattern := range patterns {
+				if match := pattern.FindStringSubmatch(line); match != nil {
+					if _, seen := definitions[match[1]]; !seen {
+						definitions[match[1]] = kind + " in " + displayPath(path)
+					}
+				}
+			}
+		}
+	}
+
+	// fish autoloads one function per file from the functions directory
+	if shell == shellFish {
+		if rcPath, err := shellRCPath(shellFish); err == nil {
+			functionsDir := filepath.Join(filepath.Dir(filepath.Dir(rcPath)), "functions")
+			if matches, err := filepath.Glob(filepath.Join(functionsDir, "*.fish")); err == nil {
+				for _, match := range matches {
+					name := strings.TrimSuffix(filepath.Base(match), ".fish")
+					if _, seen := definitions[name]; !seen {
+						definitions[name] = "function in " + displayPath(match)
+					}
+				}
+			}
+		}
+	}
+	return definitions
+}
+
+// lookPathOutside finds an executable on PATH, skipping the environment's own directories
+// and shims generated by ai-menu for other environments
+func lookPathOutside(name, envPath string) string {
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" || (envPath != "" && strings.HasPrefix(dir, envPath+string(filepath.Separator))) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+		if isShim(path) {
+			continue
+		}
+		return path
+	}
+	return ""
+}
+
+// containsString reports whether the slice contains the value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isShim reports whether the file is a wrapper script generated by ai-menu
+func isShim(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	head := make([]byte, 256)
+	n, _ := io.ReadFull(f, head)
+	return bytes.Contains(head[:n], []byte(shimMarker))
+}
+
+// findAliasConflicts checks the names ai-menu is about to define against aliases and functions in
+// the selected shells' startup files and executables on PATH. The result maps each conflicting
+// name to a description of what it would shadow.
+func findAliasConflicts(names []string, shells []string, envPath string) map[string]string {
+	conflicts := make(map[string]string)
+	if len(names) == 0 {
+		return conflicts
+	}
+
+	definitions := make([]map[string]string, 0, len(shells))
+	for _, shell := range shells {
+		definitions = append(definitions, shellDefinitions(shell))
+	}
+
+	for _, name := range names {
+		found := []string{}
+		for i := range shells {
+			if where, exists := definitions[i][name]; exists && !containsString(found, where) {
+				found = append(found, where)
+			}
+		}
+		if path := lookPathOutside(name, envPath); path != "" {
+			found = append(found, path)
+		}
+		if len(found) > 0 {
+			conflicts[name] = strings.Join(found, "; ")
+		}
+	}
+	return conflicts
+}
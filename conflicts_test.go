@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindAliasConflicts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("ZDOTDIR", "")
+
+	write := func(path, content string, mode os.FileMode) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(home, ".bashrc"), "alias cc='clang'\nkiro() { echo hi; }\n"+
+		renderManagedBlock([]string{"alias managed='pixi run managed'"}), 0644)
+	write(filepath.Join(home, ".zshrc"), "alias cc='clang'\nalias -g zg='grep'\n", 0644)
+	write(filepath.Join(home, ".config", "fish", "config.fish"), "abbr --add fa 'echo'\n", 0644)
+	write(filepath.Join(home, ".config", "fish", "functions", "ff.fish"), "function ff\nend\n", 0644)
+
+	bin := filepath.Join(home, "bin")
+	envPath := filepath.Join(home, "ai-dev-pixi")
+	write(filepath.Join(bin, "gh"), "#!/bin/sh\n", 0755)
+	write(filepath.Join(bin, "notexec"), "#!/bin/sh\n", 0644)
+	write(filepath.Join(bin, "shimmed"), "#!/bin/sh\n"+shimMarker+" for environment other\n", 0755)
+	write(filepath.Join(envPath, "bin", "own"), "#!/bin/sh\n", 0755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+filepath.Join(envPath, "bin"))
+
+	tests := []struct {
+		name   string
+		names  []string
+		shells []string
+		want   map[string]string
+	}{
+		{"no names", nil, []string{shellBash}, map[string]string{}},
+		{
+			"bash alias and function",
+			[]string{"cc", "kiro", "free"},
+			[]string{shellBash},
+			map[string]string{"cc": "alias in ~/.bashrc", "kiro": "function in ~/.bashrc"},
+		},
+		{
+			"same name in two shells",
+			[]string{"cc"},
+			[]string{shellBash, shellZsh},
+			map[string]string{"cc": "alias in ~/.bashrc; alias in ~/.zshrc"},
+		},
+		{"zsh global alias", []string{"zg"}, []string{shellZsh}, map[string]string{"zg": "alias in ~/.zshrc"}},
+		{
+			"fish abbreviation and autoloaded function",
+			[]string{"fa", "ff"},
+			[]string{shellFish},
+			map[string]string{"fa": "alias in ~/.config/fish/config.fish", "ff": "function in ~/.config/fish/functions/ff.fish"},
+		},
+		{"other shells are not checked", []string{"kiro"}, []string{shellZsh}, map[string]string{}},
+		{"managed block is ignored", []string{"managed"}, []string{shellBash}, map[string]string{}},
+		{"executable on PATH", []string{"gh"}, nil, map[string]string{"gh": filepath.Join(bin, "gh")}},
+		{
+			"non-executables, shims and the environment's own commands are ignored",
+			[]string{"notexec", "shimmed", "own"},
+			[]string{shellBash},
+			map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		got := findAliasConflicts(tt.names, tt.shells, envPath)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: findAliasConflicts = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// containerInstallPath is where a generated Dockerfile installs unless a path is chosen
+const containerInstallPath = "/opt"
+
+// defaultBaseImage is the image a generated Dockerfile builds on; the apt package names
+// used for the special tools are those of Ubuntu 24.04
+const defaultBaseImage = "ubuntu:24.04"
+
+// buildStep is one stage of a generated build: a RUN instruction of a Dockerfile or a
+// block of the shell script
+type buildStep struct {
+	comment  string
+	dir      string   // directory the commands run in, empty for the starting directory
+	commands []string // run in order, stopping at the first failure
+	path     string   // directory added to PATH for the following steps
+}
+
+// commandLine renders a command built for a real installation, including the environment
+// variables it sets on top of the inherited ones
+func commandLine(cmd *exec.Cmd) string {
+	inherited := make(map[string]bool)
+	for _, kv := range os.Environ() {
+		inherited[kv] = true
+	}
+
+	words := []string{}
+	for _, kv := range cmd.Env {
+		if !inherited[kv] {
+			words = append(words, kv)
+		}
+	}
+	return shellCommand(append(words, cmd.Args...)...)
+}
+
+// coreSpec returns the spec the plan's core dependencies pin a package to
+func coreSpec(core CoreConfig, name string) string {
+	for _, dep := range core.Dependencies() {
+		if dep.Name == name {
+			return dep.Spec()
+		}
+	}
+	return name
+}
+
+// buildSteps returns the commands that reproduce the plan's installation, built by the same
+// functions the installer runs. Steps are ordered from the least to the most likely to
+// change, so editing the tool selection keeps the system packages and pixi cached. In a
+// container the commands run as root and home is /root.
+func buildSteps(plan InstallPlan, container bool, home string) []buildStep {
+	envDir := plan.EnvDir()
+	sudo := []string{"sudo"}
+	if container {
+		sudo = nil
+	}
+
+	cliTools, specialTools, enhancers := plan.packageNames()
+
+	steps := []buildStep{}
+
+	// One apt batch for every packaged special tool, plus what the installers themselves need
+	packages := []string{"ca-certificates", "curl"}
+	if container {
+		// The vendor scripts of gh and lazygit call sudo
+		packages = append(packages, "sudo")
+	}
+	for _, tool := range specialTools {
+		if pkg, ok := aptPackage(tool); ok {
+			packages = append(packages, pkg)
+		}
+	}
+	system := buildStep{comment: "System packages"}
+	system.commands = append(system.commands,
+		shellCommand(append(sudo, "apt-get", "update")...),
+		shellCommand(append(sudo, append([]string{"apt-get", "install", "-y", "--no-install-recommends"}, packages...)...)...))
+	if container {
+		system.commands = append(system.commands, "rm -rf /var/lib/apt/lists/*")
+	}
+	steps = append(steps, system)
+
+	// Special tools installed by vendor scripts outside pixi, such as gh with its own apt repository
+	vendor := buildStep{comment: "Special tools from vendor scripts"}
+	for _, tool := range specialTools {
+		if _, packaged := aptPackage(tool); !packaged && tool != "modal" && specialInstallCommand(tool, false) != nil {
+			vendor.commands = append(vendor.commands, commandLine(specialInstallCommand(tool, false)))
+		}
+	}
+	if len(vendor.commands) > 0 {
+		steps = append(steps, vendor)
+	}
+
+	steps = append(steps, buildStep{
+		comment:  "pixi",
+		commands: []string{"command -v pixi >/dev/null 2>&1 || curl -fsSL https://pixi.sh/install.sh | bash"},
+		path:     home + "/.pixi/bin",
+	})
+
+	core := buildStep{comment: "Core dependencies", dir: envDir}
+	// Like the installer, keep an existing project so the script can be run again
+	core.commands = append(core.commands, "test -f pixi.toml || "+commandLine(exec.Command("pixi", pixiInitArgs(plan.Core.Platforms)...)))
+	specs := []string{"add"}
+	for _, dep := range plan.Core.Dependencies() {
+		specs = append(specs, dep.Spec())
+	}
+	core.commands = append(core.commands, commandLine(exec.Command("pixi", specs...)))
+	steps = append(steps, core)
+
+	// One step per backend, each tool preceded by its pixi environment in isolation mode
+	groups := map[string]*buildStep{
+		backendUv:     {comment: "uv tools", dir: envDir},
+		backendNpm:    {comment: "npm tools", dir: envDir},
+		backendScript: {comment: "CLI tools from vendor scripts", dir: envDir},
+	}
+	add := func(name, backend string, install func(pixiEnv string) *exec.Cmd) {
+		group := groups[backend]
+		pixiEnv := plan.toolPixiEnv(name)
+		if pixiEnv != "" {
+			group.commands = append(group.commands,
+				commandLine(exec.Command("pixi", toolFeatureArgs(envDir, pixiEnv, coreSpec(plan.Core, backendRuntime(backend)))...)),
+				commandLine(exec.Command("pixi", toolEnvironmentArgs(envDir, pixiEnv)...)))
+		}
+		group.commands = append(group.commands, commandLine(install(pixiEnv)))
+	}
+	for _, tool := range cliTools {
+		tool := tool
+		add(tool, getInstallBackend(tool), func(pixiEnv string) *exec.Cmd { return cliInstallCommand(tool, envDir, pixiEnv, false) })
+	}
+	for _, enhancer := range enhancers {
+		enhancer := enhancer
+		add(enhancer, getInstallBackend(enhancer), func(pixiEnv string) *exec.Cmd {
+			return enhancerInstallCommand(enhancer, envDir, pixiEnv, false)
+		})
+	}
+	if containsString(specialTools, "modal") {
+		// modal is never isolated, it goes into the default environment's Python
+		groups[backendUv].commands = append(groups[backendUv].commands, commandLine(specialInstallCommand("modal", false)))
+	}
+	for _, backend := range []string{backendUv, backendNpm, backendScript} {
+		if len(groups[backend].commands) > 0 {
+			steps = append(steps, *groups[backend])
+		}
+	}
+
+	// VS Code is not available in a container; the devcontainer export covers extensions there
+	if !container && len(plan.VSCodeExtensions) > 0 {
+		extensions := buildStep{comment: "VS Code extensions"}
+		for _, ext := range plan.VSCodeExtensions {
+			extID := strings.TrimSpace(strings.Split(ext, " - ")[0])
+			extensions.commands = append(extensions.commands, shellCommand("code", "--install-extension", extID))
+		}
+		steps = append(steps, extensions)
+	}
+
+	// Shims for every installed command, as written by an installation in shims mode
+	env := EnvironmentRecord{Name: plan.EnvName, Path: envDir, Tools: plan.toolRecords()}
+	globals := map[string]string{}
+	if containsString(specialTools, "bat") {
+		// Ubuntu installs bat as batcat
+		globals["bat"] = "batcat"
+	}
+
+	binDir := shimBinDir(envDir)
+	shims := buildStep{comment: "Shims", dir: envDir, commands: []string{shellCommand("mkdir", "-p", binDir)}, path: binDir}
+	for _, command := range environmentCommands(env, globals, plan.AliasNames) {
+		shimPath := binDir + "/" + command.Name
+		lines := strings.Split(strings.TrimSuffix(renderShim(env.Name, command.Command), "\n"), "\n")
+		shims.commands = append(shims.commands,
+			shellCommand(append([]string{"printf", `%s\n`}, lines...)...)+" > "+shellQuote(shimPath),
+			shellCommand("chmod", "+x", shimPath))
+	}
+	if len(shims.commands) > 1 {
+		steps = append(steps, shims)
+	}
+	return steps
+}
+
+// renderDockerfile returns a Dockerfile that builds an image with the plan's installation
+func renderDockerfile(plan InstallPlan, baseImage string) string {
+	var b strings.Builder
+	b.WriteString("# Generated by ai-menu export dockerfile\n")
+	if vars := plan.CredentialVars(); len(vars) > 0 {
+		b.WriteString("# API keys are not baked into the image; pass them at run time, e.g.\n")
+		fmt.Fprintf(&b, "#   docker run -e %s ...\n", strings.Join(vars, " -e "))
+	}
+	fmt.Fprintf(&b, "FROM %s\n\n", baseImage)
+	b.WriteString("ARG DEBIAN_FRONTEND=noninteractive\n")
+	for _, kv := range plan.Registries.Env() {
+		name, value, _ := strings.Cut(kv, "=")
+		fmt.Fprintf(&b, "ENV %s=%s\n", name, strconv.Quote(value))
+	}
+
+	dir := ""
+	for _, step := range buildSteps(plan, true, "/root") {
+		fmt.Fprintf(&b, "\n# %s\n", step.comment)
+		if step.dir != "" && step.dir != dir {
+			fmt.Fprintf(&b, "WORKDIR %s\n", step.dir)
+			dir = step.dir
+		}
+		fmt.Fprintf(&b, "RUN %s\n", strings.Join(step.commands, " \\\n    && "))
+		if step.path != "" {
+			fmt.Fprintf(&b, "ENV PATH=\"%s:$PATH\"\n", step.path)
+		}
+	}
+	return b.String()
+}
+
+// renderBuildScript returns a bash script that performs the plan's installation on a
+// Debian or Ubuntu machine without ai-menu
+func renderBuildScript(plan InstallPlan) string {
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env bash\n")
+	b.WriteString("# Generated by ai-menu export script\n")
+	b.WriteString("set -euo pipefail\n")
+	for _, kv := range plan.Registries.Env() {
+		fmt.Fprintf(&b, "export %s\n", shellQuote(kv))
+	}
+
+	dir := ""
+	for _, step := range buildSteps(plan, false, "$HOME") {
+		fmt.Fprintf(&b, "\n# %s\n", step.comment)
+		if step.dir != "" && step.dir != dir {
+			fmt.Fprintf(&b, "mkdir -p %s\ncd %s\n", shellQuote(step.dir), shellQuote(step.dir))
+			dir = step.dir
+		}
+		for _, command := range step.commands {
+			b.WriteString(command + "\n")
+		}
+		if step.path != "" {
+			fmt.Fprintf(&b, "export PATH=\"%s:$PATH\"\n", step.path)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n%s\n", shellCommand("echo", "Add "+shimBinDir(plan.EnvDir())+" to your PATH to use the tools"))
+	return b.String()
+}
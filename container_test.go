@@ -0,0 +1,135 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// containerTestPlan selects a tool of every backend the build steps handle
+func containerTestPlan() InstallPlan {
+	return InstallPlan{
+		InstallPath:      "/opt",
+		EnvName:          "team",
+		Core:             CoreConfig{NodeVersion: "22.*", PythonVersion: "3.12.*", Platforms: []string{"linux-64"}},
+		CLITools:         []string{"Codex by OpenAI", "Kimi by MoonshotAI", "Goose"},
+		VSCodeExtensions: []string{"saoudrizwan.claude-dev - Cline"},
+		SpecialTools:     []string{"jq - JSON processor", "gh - GitHub CLI", "modal - Serverless cloud platform CLI", "bat - Better cat with syntax highlighting"},
+		Registries:       RegistryConfig{Npm: "https://npm.example.com"},
+	}
+}
+
+func TestBuildSteps(t *testing.T) {
+	isolated := containerTestPlan()
+	isolated.Isolate = true
+	empty := containerTestPlan()
+	empty.CLITools, empty.VSCodeExtensions, empty.SpecialTools = nil, nil, nil
+
+	tests := []struct {
+		name      string
+		plan      InstallPlan
+		container bool
+		steps     []string
+		commands  map[string][]string // commands expected in a step, in order
+	}{
+		{"container", containerTestPlan(), true,
+			[]string{"System packages", "Special tools from vendor scripts", "pixi", "Core dependencies", "uv tools", "npm tools", "CLI tools from vendor scripts", "Shims"},
+			map[string][]string{
+				"System packages":   {"apt-get update", "apt-get install -y --no-install-recommends ca-certificates curl sudo jq bat", "rm -rf /var/lib/apt/lists/*"},
+				"Core dependencies": {"test -f pixi.toml || pixi init --platform linux-64", "pixi add 'nodejs=22.*' 'python=3.12.*' uv"},
+				"uv tools":          {"pixi run uv tool install --python 3.13 kimi-cli", "pixi run uv pip install modal"},
+				"npm tools":         {"pixi run npm install -g @openai/codex"},
+			}},
+		// Outside a container apt needs sudo and the VS Code extensions are installed too
+		{"script", containerTestPlan(), false,
+			[]string{"System packages", "Special tools from vendor scripts", "pixi", "Core dependencies", "uv tools", "npm tools", "CLI tools from vendor scripts", "VS Code extensions", "Shims"},
+			map[string][]string{
+				"System packages":    {"sudo apt-get update", "sudo apt-get install -y --no-install-recommends ca-certificates curl jq bat"},
+				"VS Code extensions": {"code --install-extension saoudrizwan.claude-dev"},
+			}},
+		{"isolated tools", isolated, true,
+			[]string{"System packages", "Special tools from vendor scripts", "pixi", "Core dependencies", "uv tools", "npm tools", "CLI tools from vendor scripts", "Shims"},
+			map[string][]string{
+				"uv tools": {
+					"pixi add --manifest-path /opt/team --feature kimi uv",
+					"pixi workspace environment add --manifest-path /opt/team --feature kimi --force kimi",
+					"UV_TOOL_DIR=/opt/team/.pixi/uv-tools/kimi UV_TOOL_BIN_DIR=/opt/team/.pixi/envs/kimi/bin pixi run -e kimi uv tool install --python 3.13 kimi-cli",
+					// modal stays in the default environment
+					"pixi run uv pip install modal",
+				},
+				"npm tools": {
+					"pixi add --manifest-path /opt/team --feature codex 'nodejs=22.*'",
+					"pixi workspace environment add --manifest-path /opt/team --feature codex --force codex",
+					"pixi run -e codex npm install -g @openai/codex",
+				},
+			}},
+		{"nothing selected", empty, true, []string{"System packages", "pixi", "Core dependencies"}, nil},
+	}
+	for _, tt := range tests {
+		steps := buildSteps(tt.plan, tt.container, "/root")
+		comments := []string{}
+		byComment := map[string]buildStep{}
+		for _, step := range steps {
+			comments = append(comments, step.comment)
+			byComment[step.comment] = step
+		}
+		if !reflect.DeepEqual(comments, tt.steps) {
+			t.Errorf("%s: steps %q, want %q", tt.name, comments, tt.steps)
+		}
+		for comment, want := range tt.commands {
+			if got := byComment[comment].commands; !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: %s commands\n%q\nwant\n%q", tt.name, comment, got, want)
+			}
+		}
+		// The shims are written by printf so the build needs no ai-menu
+		if shims, ok := byComment["Shims"]; ok {
+			if shims.path != "/opt/team/bin" || !containsString(shims.commands, "chmod +x /opt/team/bin/bat") {
+				t.Errorf("%s: shims step %+v", tt.name, shims)
+			}
+		}
+	}
+}
+
+func TestRenderDockerfile(t *testing.T) {
+	dockerfile := renderDockerfile(containerTestPlan(), defaultBaseImage)
+
+	// Each line must appear, in this order
+	want := []string{
+		"# Generated by ai-menu export dockerfile",
+		"#   docker run -e OPENAI_API_KEY -e MOONSHOT_API_KEY -e GH_TOKEN -e MODAL_TOKEN_ID -e MODAL_TOKEN_SECRET ...",
+		"FROM ubuntu:24.04",
+		"ARG DEBIAN_FRONTEND=noninteractive",
+		`ENV NPM_CONFIG_REGISTRY="https://npm.example.com"`,
+		"# System packages",
+		"RUN apt-get update \\",
+		"    && apt-get install -y --no-install-recommends ca-certificates curl sudo jq bat \\",
+		"    && rm -rf /var/lib/apt/lists/*",
+		"# pixi",
+		`ENV PATH="/root/.pixi/bin:$PATH"`,
+		"# Core dependencies",
+		"WORKDIR /opt/team",
+		"RUN test -f pixi.toml || pixi init --platform linux-64 \\",
+		"# uv tools",
+		"RUN pixi run uv tool install --python 3.13 kimi-cli \\",
+		"# Shims",
+		`ENV PATH="/opt/team/bin:$PATH"`,
+	}
+	lines := strings.Split(dockerfile, "\n")
+	next := 0
+	for _, line := range lines {
+		if next < len(want) && line == want[next] {
+			next++
+		}
+	}
+	if next < len(want) {
+		t.Errorf("Dockerfile is missing %q after the earlier lines:\n%s", want[next], dockerfile)
+	}
+
+	// WORKDIR is only repeated when the directory changes
+	if count := strings.Count(dockerfile, "WORKDIR "); count != 1 {
+		t.Errorf("Dockerfile has %d WORKDIR instructions, want 1", count)
+	}
+	if strings.Contains(dockerfile, "code --install-extension") {
+		t.Error("Dockerfile installs VS Code extensions")
+	}
+}
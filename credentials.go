@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Files holding an environment's credentials and the wrapper that loads them
+const (
+	credentialsFileName   = "credentials.env"
+	credentialsLoaderName = "with-credentials"
+)
+
+// credentialsPath returns the env file holding the environment's API keys
+func credentialsPath(envPath string) string {
+	return filepath.Join(envPath, credentialsFileName)
+}
+
+// credentialsLoaderPath returns the wrapper that exports the env file before running a command
+func credentialsLoaderPath(envPath string) string {
+	return filepath.Join(envPath, credentialsLoaderName)
+}
+
+// hasCredentials reports whether credentials have been saved for the environment
+func hasCredentials(envPath string) bool {
+	_, err := os.Stat(credentialsPath(envPath))
+	return err == nil
+}
+
+// withCredentials prefixes a command with the loader when the environment has saved credentials
+func withCredentials(envPath, command string) string {
+	if _, err := os.Stat(credentialsLoaderPath(envPath)); err != nil || !hasCredentials(envPath) {
+		return command
+	}
+	return credentialsLoaderPath(envPath) + " " + command
+}
+
+// credentialVarsFor returns the environment variables needed by the selected catalog items, in order
+func credentialVarsFor(items []string) []string {
+	vars := []string{}
+	for _, item := range items {
+		entry, ok := lookupCatalogEntry(item)
+		if !ok {
+			continue
+		}
+		for _, name := range entry.Credentials {
+			if !containsString(vars, name) {
+				vars = append(vars, name)
+			}
+		}
+	}
+	return vars
+}
+
+// quoteEnvValue quotes a value for a POSIX sh env file
+func quoteEnvValue(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// unquoteEnvValue reverses quoteEnvValue, accepting unquoted values as written by hand
+func unquoteEnvValue(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		return strings.ReplaceAll(value[1:len(value)-1], `'\''`, "'")
+	}
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// LoadCredentials reads the environment's saved credentials; a missing file yields no credentials
+func LoadCredentials(envPath string) (map[string]string, error) {
+	credentials := make(map[string]string)
+
+	file, err := os.Open(credentialsPath(envPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return credentials, nil
+		}
+		return credentials, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		name, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		credentials[strings.TrimSpace(name)] = unquoteEnvValue(strings.TrimSpace(value))
+	}
+	return credentials, scanner.Err()
+}
+
+// SaveCredentials merges the given values into the environment's env file, which is only readable
+// by the owner, and writes the wrapper that loads it
+func SaveCredentials(envPath string, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	credentials, err := LoadCredentials(envPath)
+	if err != nil {
+		return err
+	}
+	for name, value := range values {
+		credentials[name] = value
+	}
+
+	names := make([]string, 0, len(credentials))
+	for name := range credentials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# API keys for tools in this environment, written by ai-menu. Keep this file private.\n")
+	for _, name := range names {
+		b.WriteString(fmt.Sprintf("%s=%s\n", name, quoteEnvValue(credentials[name])))
+	}
+
+	if err := os.MkdirAll(envPath, 0755); err != nil {
+		return err
+	}
+
+	// Create the temporary file with owner-only permissions so the keys are never world readable
+	tmp, err := os.CreateTemp(envPath, "."+credentialsFileName+"-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.WriteString(b.String()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, credentialsPath(envPath)); err != nil {
+		return err
+	}
+
+	return writeCredentialsLoader(envPath)
+}
+
+// writeCredentialsLoader writes the wrapper that exports the env file and runs its arguments
+func writeCredentialsLoader(envPath string) error {
+	script := fmt.Sprintf("#!/bin/sh\n%s to load %s\nset -a\n[ -f %q ] && . %q\nset +a\nexec \"$@\"\n",
+		shimMarker, credentialsFileName, credentialsPath(envPath), credentialsPath(envPath))
+	return os.WriteFile(credentialsLoaderPath(envPath), []byte(script), 0755)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveCredentials(t *testing.T) {
+	envPath := t.TempDir()
+	values := map[string]string{
+		"OPENAI_API_KEY":    "sk-plain",
+		"ANTHROPIC_API_KEY": `it's $HOME "quoted"`,
+		"GEMINI_API_KEY":    "with spaces and `backticks`",
+	}
+
+	// Nothing is wrapped until credentials are saved
+	if got := withCredentials(envPath, "pixi run codex"); got != "pixi run codex" {
+		t.Errorf("withCredentials without credentials = %q", got)
+	}
+
+	if err := SaveCredentials(envPath, map[string]string{"OPENAI_API_KEY": "sk-old"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveCredentials(envPath, values); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(credentialsPath(envPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("%s mode = %v, want 0600", credentialsFileName, info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(envPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("environment contains %v, want only %s and %s", entries, credentialsFileName, credentialsLoaderName)
+	}
+
+	loaded, err := LoadCredentials(envPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range values {
+		if loaded[name] != value {
+			t.Errorf("loaded %s = %q, want %q", name, loaded[name], value)
+		}
+	}
+
+	want := filepath.Join(envPath, credentialsLoaderName) + " pixi run codex"
+	if got := withCredentials(envPath, "pixi run codex"); got != want {
+		t.Errorf("withCredentials = %q, want %q", got, want)
+	}
+
+	// The shell reads back exactly the saved values
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	for name, value := range values {
+		out, err := exec.Command(credentialsLoaderPath(envPath), "sh", "-c", `printf %s "$`+name+`"`).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != value {
+			t.Errorf("shell sees %s = %q, want %q", name, out, value)
+		}
+	}
+}
+
+func TestQuoteEnvValue(t *testing.T) {
+	tests := []struct {
+		value, quoted string
+	}{
+		{"plain", `'plain'`},
+		{"it's", `'it'\''s'`},
+		{"$HOME `x`", "'$HOME `x`'"},
+		{"", `''`},
+	}
+	for _, tt := range tests {
+		if got := quoteEnvValue(tt.value); got != tt.quoted {
+			t.Errorf("quoteEnvValue(%q) = %q, want %q", tt.value, got, tt.quoted)
+		}
+		if got := unquoteEnvValue(tt.quoted); got != tt.value {
+			t.Errorf("unquoteEnvValue(%q) = %q, want %q", tt.quoted, got, tt.value)
+		}
+	}
+
+	// Values written by hand may be double quoted or bare
+	for value, want := range map[string]string{`"double"`: "double", "bare": "bare"} {
+		if got := unquoteEnvValue(value); got != want {
+			t.Errorf("unquoteEnvValue(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -1,94 +1,235 @@
 package main
 
+import (
+	"fmt"
+	"strings"
+)
+
+// CatalogEntry describes a tool or extension offered in the selection views
+type CatalogEntry struct {
+	Name      string   // display name shown in the selection views
+	Package   string   // package or identifier passed to the installer
+	Platforms []string // pixi platforms the entry supports; empty means every platform
+	// Completion prints the tool's completion script; {shell} is replaced by bash, zsh or fish
+	Completion string
+	// Credentials lists the environment variables holding the API keys or tokens the tool needs
+	Credentials []string
+	// Tags are extra words the selection filter matches, such as what the tool is for
+	Tags []string
+	// Description, Homepage and License are shown in the detail pane. License stays empty
+	// unless taken from the project itself.
+	Description string
+	Homepage    string
+	License     string
+}
+
+// Platform groups shared by catalog entries
+var (
+	linuxPlatforms = []string{"linux-64", "linux-aarch64"}
+	unixPlatforms  = []string{"linux-64", "linux-aarch64", "osx-64", "osx-arm64"}
+)
+
+// cliToolCatalog lists the AI CLI tools
+var cliToolCatalog = []CatalogEntry{
+	{Name: "Amp by Sourcegraph", Package: "@sourcegraph/amp@latest", Credentials: []string{"AMP_API_KEY"}, Tags: []string{"agent"},
+		Description: "Agentic coding tool from Sourcegraph", Homepage: "https://ampcode.com"},
+	{Name: "Auggie by Augment Code", Package: "@augmentcode/auggie", Credentials: []string{"AUGMENT_SESSION_AUTH"}, Tags: []string{"agent"},
+		Description: "Augment Code's agent for the terminal", Homepage: "https://www.augmentcode.com"},
+	{Name: "Codex by OpenAI", Package: "@openai/codex", Completion: "codex completion {shell}", Credentials: []string{"OPENAI_API_KEY"}, Tags: []string{"agent", "gpt"},
+		Description: "OpenAI's coding agent that runs locally", Homepage: "https://github.com/openai/codex", License: "Apache-2.0"},
+	{Name: "Droid by Factory AI", Package: "droid", Platforms: unixPlatforms, Credentials: []string{"FACTORY_API_KEY"}, Tags: []string{"agent"},
+		Description: "Factory's software development agent", Homepage: "https://factory.ai"},
+	{Name: "Forgecode", Package: "forgecode@latest", Tags: []string{"agent"},
+		Description: "AI pair programmer for the terminal", Homepage: "https://forgecode.dev", License: "Apache-2.0"},
+	{Name: "Gemini CLI by Google", Package: "@google/gemini-cli", Credentials: []string{"GEMINI_API_KEY"}, Tags: []string{"agent"},
+		Description: "Google's open source AI agent for the terminal", Homepage: "https://github.com/google-gemini/gemini-cli", License: "Apache-2.0"},
+	{Name: "Goose", Package: "goose", Platforms: unixPlatforms, Tags: []string{"agent", "block", "mcp"},
+		Description: "Extensible on-machine agent from Block", Homepage: "https://github.com/block/goose", License: "Apache-2.0"},
+	{Name: "Grok CLI", Package: "@vibe-kit/grok-cli", Credentials: []string{"GROK_API_KEY"}, Tags: []string{"agent", "xai"},
+		Description: "Conversational coding agent powered by Grok", Homepage: "https://github.com/superagent-ai/grok-cli", License: "MIT"},
+	{Name: "Kimi by MoonshotAI", Package: "kimi-cli", Credentials: []string{"MOONSHOT_API_KEY"}, Tags: []string{"agent"},
+		Description: "MoonshotAI's coding agent for the terminal", Homepage: "https://github.com/MoonshotAI/kimi-cli", License: "Apache-2.0"},
+	{Name: "Kiro CLI by AWS", Package: "kiro", Platforms: unixPlatforms, Tags: []string{"agent", "amazon"},
+		Description: "The Kiro agent for the terminal", Homepage: "https://kiro.dev"},
+	{Name: "OpenCode CLI", Package: "opencode-ai", Credentials: []string{"ANTHROPIC_API_KEY"}, Tags: []string{"agent", "sst"},
+		Description: "Open source terminal coding agent", Homepage: "https://opencode.ai", License: "MIT"},
+	{Name: "OpenHands", Package: "openhands", Credentials: []string{"LLM_API_KEY"}, Tags: []string{"agent", "all-hands"},
+		Description: "Open source software development agent", Homepage: "https://github.com/All-Hands-AI/OpenHands", License: "MIT"},
+	{Name: "Plandex", Package: "plandex", Platforms: unixPlatforms, Credentials: []string{"OPENROUTER_API_KEY"}, Tags: []string{"agent", "openrouter"},
+		Description: "Terminal agent for large, multi-step tasks", Homepage: "https://plandex.ai", License: "MIT"},
+	{Name: "Qodo CLI", Package: "@qodo/command", Credentials: []string{"QODO_API_KEY"}, Tags: []string{"agent", "review"},
+		Description: "Agents for code review and generation", Homepage: "https://www.qodo.ai"},
+	{Name: "Qoder by Qwen", Package: "@qoder-ai/qodercli", Tags: []string{"agent", "alibaba"},
+		Description: "Qoder's agentic coding CLI", Homepage: "https://qoder.com"},
+}
+
+// vscodeExtensionCatalog lists the VS Code extensions
+var vscodeExtensionCatalog = []CatalogEntry{
+	{Name: "augment.vscode-augment - Augment Code", Package: "augment.vscode-augment", Tags: []string{"agent"},
+		Homepage: "https://marketplace.visualstudio.com/items?itemName=augment.vscode-augment"},
+	{Name: "kilocode.kilo-code - Kilo Code", Package: "kilocode.kilo-code", Tags: []string{"agent"},
+		Homepage: "https://marketplace.visualstudio.com/items?itemName=kilocode.kilo-code", License: "Apache-2.0"},
+	{Name: "rooveterinaryinc.roo-cline - Roo Code", Package: "rooveterinaryinc.roo-cline", Tags: []string{"agent", "cline"},
+		Homepage: "https://marketplace.visualstudio.com/items?itemName=rooveterinaryinc.roo-cline", License: "Apache-2.0"},
+	{Name: "saoudrizwan.claude-dev - Cline", Package: "saoudrizwan.claude-dev", Tags: []string{"agent", "claude"},
+		Homepage: "https://marketplace.visualstudio.com/items?itemName=saoudrizwan.claude-dev", License: "Apache-2.0"},
+	{Name: "zencoderai.zencoder - Zencoder", Package: "zencoderai.zencoder", Tags: []string{"agent"},
+		Homepage: "https://marketplace.visualstudio.com/items?itemName=zencoderai.zencoder"},
+}
+
+// specialToolCatalog lists the special tools; most are installed with apt and are Linux only
+var specialToolCatalog = []CatalogEntry{
+	{Name: "helm - Kubernetes package manager", Package: "helm", Platforms: unixPlatforms, Completion: "helm completion {shell}", Tags: []string{"k8s", "charts"},
+		Homepage: "https://helm.sh", License: "Apache-2.0"},
+	{Name: "gh - GitHub CLI", Package: "gh", Platforms: linuxPlatforms, Completion: "gh completion -s {shell}", Credentials: []string{"GH_TOKEN"}, Tags: []string{"git", "pull requests"},
+		Homepage: "https://cli.github.com", License: "MIT"},
+	{Name: "ripgrep - Fast search tool (rg)", Package: "ripgrep", Platforms: linuxPlatforms, Completion: "rg --generate complete-{shell}", Tags: []string{"grep"},
+		Homepage: "https://github.com/BurntSushi/ripgrep", License: "MIT OR Unlicense"},
+	{Name: "jq - JSON processor", Package: "jq", Platforms: linuxPlatforms, Tags: []string{"json"},
+		Homepage: "https://jqlang.org", License: "MIT"},
+	{Name: "yq - YAML processor", Package: "yq", Platforms: linuxPlatforms, Completion: "yq shell-completion {shell}", Tags: []string{"yaml"},
+		Homepage: "https://github.com/mikefarah/yq", License: "MIT"},
+	{Name: "bat - Better cat with syntax highlighting", Package: "bat", Platforms: linuxPlatforms, Tags: []string{"pager"},
+		Homepage: "https://github.com/sharkdp/bat", License: "MIT OR Apache-2.0"},
+	{Name: "exa - Modern ls replacement (installs eza)", Package: "exa", Platforms: linuxPlatforms, Tags: []string{"files"},
+		Homepage: "https://eza.rocks", License: "EUPL-1.2"},
+	{Name: "fd - Better find alternative", Package: "fd", Platforms: linuxPlatforms, Tags: []string{"files", "search"},
+		Homepage: "https://github.com/sharkdp/fd", License: "MIT OR Apache-2.0"},
+	{Name: "lazygit - Git TUI", Package: "lazygit", Platforms: []string{"linux-64"}, Tags: []string{"git"},
+		Homepage: "https://github.com/jesseduffield/lazygit", License: "MIT"},
+	{Name: "modal - Serverless cloud platform CLI", Package: "modal", Credentials: []string{"MODAL_TOKEN_ID", "MODAL_TOKEN_SECRET"}, Tags: []string{"gpu", "python"},
+		Homepage: "https://modal.com", License: "Apache-2.0"},
+}
+
+// cliEnhancerCatalog lists the CLI tool enhancers
+var cliEnhancerCatalog = []CatalogEntry{
+	{Name: "Claude Flow by ruvnet - Claude CLI enhancer", Package: "claude-flow@alpha", Credentials: []string{"ANTHROPIC_API_KEY"}, Tags: []string{"agents", "swarm", "mcp"},
+		Description: "Agent orchestration and swarms for Claude Code", Homepage: "https://github.com/ruvnet/claude-flow", License: "MIT"},
+	{Name: "Spec Kit by GitHub - GitHub specification toolkit", Package: "specify-cli", Tags: []string{"spec-driven", "sdd"},
+		Description: "Spec-driven development with AI agents", Homepage: "https://github.com/github/spec-kit", License: "MIT"},
+}
+
+// catalogNames returns the display names of the given catalog entries
+func catalogNames(entries []CatalogEntry) []string {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+// lookupCatalogEntry finds an entry by display name across all catalogs
+func lookupCatalogEntry(displayName string) (CatalogEntry, bool) {
+	for _, catalog := range [][]CatalogEntry{cliToolCatalog, vscodeExtensionCatalog, specialToolCatalog, cliEnhancerCatalog} {
+		for _, entry := range catalog {
+			if entry.Name == displayName {
+				return entry, true
+			}
+		}
+	}
+	return CatalogEntry{}, false
+}
+
+// lookupCatalogPackage finds an entry by package name across all catalogs
+func lookupCatalogPackage(packageName string) (CatalogEntry, bool) {
+	for _, catalog := range [][]CatalogEntry{cliToolCatalog, vscodeExtensionCatalog, specialToolCatalog, cliEnhancerCatalog} {
+		for _, entry := range catalog {
+			if entry.Package == packageName {
+				return entry, true
+			}
+		}
+	}
+	return CatalogEntry{}, false
+}
+
+// SupportsPlatform reports whether the entry can be installed on the given pixi platform
+func (e CatalogEntry) SupportsPlatform(platform string) bool {
+	if len(e.Platforms) == 0 {
+		return true
+	}
+	for _, p := range e.Platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
 // getCLITools returns the list of available CLI tools
 func getCLITools() []string {
-	return []string{
-		"Amp by Sourcegraph",
-		"Auggie by Augment Code",
-		"Codex by OpenAI",
-		"Droid by Factory AI",
-		"Forgecode",
-		"Gemini CLI by Google",
-		"Goose",
-		"Grok CLI",
-		"Kimi by MoonshotAI",
-		"Kiro CLI by AWS",
-		"OpenCode CLI",
-		"OpenHands",
-		"Plandex",
-		"Qodo CLI",
-		"Qoder by Qwen",
-	}
+	return catalogNames(cliToolCatalog)
 }
 
 // getPackageNameForCLI maps display names to package names for installation
 func getPackageNameForCLI(displayName string) string {
-	packageMap := map[string]string{
-		"Amp by Sourcegraph":      "@sourcegraph/amp@latest",
-		"Auggie by Augment Code":  "@augmentcode/auggie",
-		"Codex by OpenAI":         "@openai/codex",
-		"Droid by Factory AI":     "droid",
-		"Forgecode":               "forgecode@latest",
-		"Gemini CLI by Google":    "@google/gemini-cli",
-		"Goose":                   "goose",
-		"Grok CLI":                "@vibe-kit/grok-cli",
-		"Kimi by MoonshotAI":      "kimi-cli",
-		"Kiro CLI by AWS":         "kiro",
-		"OpenCode CLI":            "opencode-ai",
-		"OpenHands":               "openhands",
-		"Plandex":                 "plandex",
-		"Qodo CLI":                "@qodo/command",
-		"Qoder by Qwen":           "@qoder-ai/qodercli",
-	}
-	if pkg, exists := packageMap[displayName]; exists {
-		return pkg
+	if entry, exists := lookupCatalogEntry(displayName); exists {
+		return entry.Package
 	}
 	return displayName
 }
 
 // getVSCodeExtensions returns the list of available VS Code extensions
 func getVSCodeExtensions() []string {
-	return []string{
-		"augment.vscode-augment - Augment Code",
-		"kilocode.kilo-code - Kilo Code",
-		"rooveterinaryinc.roo-cline - Roo Code",
-		"saoudrizwan.claude-dev - Cline",
-		"zencoderai.zencoder - Zencoder",
-	}
+	return catalogNames(vscodeExtensionCatalog)
 }
 
 // getSpecialTools returns the list of special tools
 func getSpecialTools() []string {
-	return []string{
-		"helm - Kubernetes package manager",
-		"gh - GitHub CLI",
-		"ripgrep - Fast search tool (rg)",
-		"jq - JSON processor",
-		"yq - YAML processor",
-		"bat - Better cat with syntax highlighting",
-		"exa - Modern ls replacement (installs eza)",
-		"fd - Better find alternative",
-		"lazygit - Git TUI",
-		"modal - Serverless cloud platform CLI",
-	}
+	return catalogNames(specialToolCatalog)
 }
 
 // getCLIEnhancers returns the list of CLI tool enhancers
 func getCLIEnhancers() []string {
-	return []string{
-		"Claude Flow by ruvnet - Claude CLI enhancer",
-		"Spec Kit by GitHub - GitHub specification toolkit",
-	}
+	return catalogNames(cliEnhancerCatalog)
 }
 
 // getPackageNameForCLIEnhancer maps display names to package names for installation
 func getPackageNameForCLIEnhancer(displayName string) string {
-	packageMap := map[string]string{
-		"Claude Flow by ruvnet - Claude CLI enhancer":    "claude-flow@alpha",
-		"Spec Kit by GitHub - GitHub specification toolkit": "specify-cli",
-	}
-	if pkg, exists := packageMap[displayName]; exists {
-		return pkg
+	if entry, exists := lookupCatalogEntry(displayName); exists {
+		return entry.Package
 	}
 	return displayName
 }
+
+// catalogKeys returns the short names a catalog entry can be referred to by on the command line
+func catalogKeys(entry CatalogEntry) []string {
+	keys := []string{entry.Name, entry.Package, getAliasName(entry.Package), getCommandName(entry.Package)}
+
+	// "ripgrep - Fast search tool (rg)" is known as ripgrep and rg,
+	// "saoudrizwan.claude-dev - Cline" as saoudrizwan.claude-dev and Cline
+	title, description, hasDescription := strings.Cut(entry.Name, " - ")
+	keys = append(keys, title, strings.Fields(title)[0])
+	if hasDescription {
+		keys = append(keys, description)
+		if open := strings.LastIndex(description, "("); open >= 0 && strings.HasSuffix(description, ")") {
+			keys = append(keys, description[open+1:len(description)-1])
+		}
+	}
+	return keys
+}
+
+// resolveCatalogItems maps command-line names to the display names of catalog entries
+func resolveCatalogItems(catalog []CatalogEntry, names []string) ([]string, error) {
+	resolved := []string{}
+	for _, name := range names {
+		found := false
+		for _, entry := range catalog {
+			for _, key := range catalogKeys(entry) {
+				if strings.EqualFold(key, name) {
+					found = true
+					break
+				}
+			}
+			if found {
+				if !containsString(resolved, entry.Name) {
+					resolved = append(resolved, entry.Name)
+				}
+				break
+			}
+		}
+		if !found {
+			return resolved, fmt.Errorf("unknown item %q", name)
+		}
+	}
+	return resolved, nil
+}
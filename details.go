@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// backendDescriptions explains each install backend in the detail pane
+var backendDescriptions = map[string]string{
+	backendNpm:    "npm, installed globally inside the pixi environment",
+	backendUv:     "uv, installed inside the pixi environment",
+	backendScript: "vendor install script, installed outside pixi",
+	backendApt:    "apt, installed as a system package",
+	backendVSCode: "VS Code, installed with the code CLI",
+}
+
+// itemInstallCommand returns the command the installer runs for a catalog item, with the
+// plan's environment and isolation settings
+func itemInstallCommand(plan InstallPlan, category string, entry CatalogEntry) string {
+	envDir := plan.EnvDir()
+	switch category {
+	case categoryCLI:
+		return commandLine(cliInstallCommand(entry.Package, envDir, plan.toolPixiEnv(entry.Package), false))
+	case categoryEnhancer:
+		return commandLine(enhancerInstallCommand(entry.Package, envDir, plan.toolPixiEnv(entry.Package), false))
+	case categoryVSCode:
+		return shellCommand("code", "--install-extension", entry.Package)
+	case categorySpecial:
+		if cmd := specialInstallCommand(entry.Package, false); cmd != nil {
+			return commandLine(cmd)
+		}
+	}
+	return ""
+}
+
+// installedVersion describes whether the target environment has the tool, and which version
+func (m model) installedVersion(category string, entry CatalogEntry) string {
+	// Only the tools living in the pixi environment are recorded in the state file
+	if category == categoryVSCode || category == categorySpecial && entry.Package != "modal" {
+		return "not tracked by ai-menu"
+	}
+
+	for _, env := range m.environments {
+		if env.Name != m.envName {
+			continue
+		}
+		tool, ok := env.FindTool(entry.Package)
+		switch {
+		case !ok:
+			return "not installed in " + env.Name
+		case tool.Version == "":
+			return "installed in " + env.Name + ", version unknown"
+		default:
+			return tool.Version + " in " + env.Name
+		}
+	}
+	return "not installed in " + m.envName
+}
+
+// toolDetails renders the detail pane of a catalog item, width cells wide including its border
+func (m model) toolDetails(category, name string, width int) string {
+	entry, ok := lookupCatalogEntry(name)
+	if !ok {
+		return ""
+	}
+
+	label, description, _ := strings.Cut(entry.Name, " - ")
+	if entry.Description != "" {
+		description = entry.Description
+	}
+
+	plan := InstallPlan{InstallPath: m.installPath, EnvName: m.envName, Isolate: m.isolate}
+	backend := categoryBackend(category, entry.Package)
+	command := itemInstallCommand(plan, category, entry)
+
+	// Unsourced licenses are left empty in the catalog
+	license := entry.License
+	if license == "" {
+		license = "unknown"
+	}
+
+	var b strings.Builder
+	b.WriteString(selectedItemStyle.Render(label))
+	if description != "" {
+		b.WriteString("\n" + normalItemStyle.Render(description))
+	}
+	b.WriteString("\n")
+
+	// Long values such as vendor install scripts wrap under the value column
+	const labelWidth = 11
+	paneStyle := detailStyle.Width(width - detailStyle.GetHorizontalBorderSize())
+	valueStyle := lipgloss.NewStyle().Width(max(10, paneStyle.GetWidth()-paneStyle.GetHorizontalPadding()-labelWidth))
+	field := func(name, value string) {
+		if value != "" {
+			label := uncheckedStyle.Width(labelWidth).Render(name + ":")
+			b.WriteString("\n" + lipgloss.JoinHorizontal(lipgloss.Top, label, valueStyle.Render(value)))
+		}
+	}
+	field("Backend", backendDescriptions[backend])
+	field("Command", command)
+	if strings.Contains(command, "sudo ") {
+		field("Sudo", "required")
+	}
+	field("API keys", strings.Join(entry.Credentials, ", "))
+	field("Homepage", entry.Homepage)
+	field("License", license)
+	if !entry.SupportsPlatform(m.platform) {
+		field("Platforms", strings.Join(entry.Platforms, ", "))
+	}
+	field("Installed", m.installedVersion(category, entry))
+
+	return paneStyle.Render(b.String())
+}
@@ -0,0 +1,418 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Outcomes of a diagnostic check
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// Check is the outcome of one diagnostic, with a suggested fix when it did not pass
+type Check struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// prerequisite describes an external command ai-menu relies on
+type prerequisite struct {
+	command  string
+	required bool   // installs cannot work at all without it
+	purpose  string // what it is needed for
+	fix      string
+}
+
+// Prerequisites checked by doctor and by the pre-flight check of an installation
+var (
+	pixiPrerequisite = prerequisite{"pixi", true, "creating the environment and running every tool", "curl -fsSL https://pixi.sh/install.sh | bash"}
+	curlPrerequisite = prerequisite{"curl", false, "vendor install scripts (droid, goose, kiro, plandex, helm, gh, lazygit)", "sudo apt-get install -y curl"}
+	codePrerequisite = prerequisite{"code", false, "VS Code extension installs", "install VS Code and run 'Shell Command: Install code command in PATH'"}
+	sudoPrerequisite = prerequisite{"sudo", false, "apt installs of the special tools", "run as root or install sudo"}
+)
+
+// prerequisites lists the external commands checked by doctor
+var prerequisites = []prerequisite{pixiPrerequisite, curlPrerequisite, codePrerequisite, sudoPrerequisite}
+
+// credentialName matches valid environment variable names
+var credentialName = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// checkCommand verifies that an external command is on PATH
+func checkCommand(p prerequisite) Check {
+	check := Check{Name: p.command}
+	if path, err := exec.LookPath(p.command); err == nil {
+		check.Status = checkOK
+		check.Detail = path
+		return check
+	}
+
+	check.Status = checkWarn
+	if p.required {
+		check.Status = checkFail
+	}
+	check.Detail = "not found on PATH; needed for " + p.purpose
+	check.Fix = p.fix
+	return check
+}
+
+// checkPrerequisites verifies that the external commands are on PATH
+func checkPrerequisites() []Check {
+	checks := make([]Check, 0, len(prerequisites))
+	for _, p := range prerequisites {
+		checks = append(checks, checkCommand(p))
+	}
+	return checks
+}
+
+// checkState verifies that the state file is readable and the active environment still exists
+func checkState() []Check {
+	state, err := LoadState()
+	if err != nil {
+		path, _ := statePath()
+		return []Check{{Name: "state file", Status: checkFail, Detail: err.Error(), Fix: fmt.Sprintf("fix or delete %s", path)}}
+	}
+
+	env := state.ActiveEnvironment()
+	switch {
+	case state.Active == "":
+		return []Check{{Name: "active environment", Status: checkWarn, Detail: "none", Fix: "install tools or run 'ai-menu' and press s on an environment"}}
+	case env == nil:
+		return []Check{{Name: "active environment", Status: checkFail, Detail: fmt.Sprintf("%s is not in the state file", state.Active), Fix: "switch to another environment with 's' on the welcome screen"}}
+	case !env.Exists():
+		return []Check{{Name: "active environment", Status: checkFail, Detail: fmt.Sprintf("%s has no pixi.toml at %s", env.Name, env.Path), Fix: "reinstall into the environment or remove it from the state file"}}
+	}
+	return []Check{{Name: "active environment", Status: checkOK, Detail: fmt.Sprintf("%s (%s)", env.Name, env.Path)}}
+}
+
+// checkEnvironmentName verifies that the environment name is not registered at another path,
+// which the installation would refuse. A default environment in another project gets a name
+// of its own instead.
+func checkEnvironmentName(name, envDir string) Check {
+	check := Check{Name: "environment name", Status: checkOK, Detail: name}
+	state, err := LoadState()
+	if err != nil {
+		return check
+	}
+	existing := state.Environment(name)
+	env, err := state.UpsertEnvironment(name, envDir)
+	switch {
+	case err != nil:
+		check.Status = checkFail
+		check.Detail = fmt.Sprintf("%s is already registered at %s", name, existing.Path)
+		check.Fix = fmt.Sprintf("choose another environment name, or install into %s", filepath.Dir(existing.Path))
+	case env.Name != name:
+		check.Detail = fmt.Sprintf("%s (registered as %s, since %s is used at %s)", name, env.Name, name, existing.Path)
+	}
+	return check
+}
+
+// checkPixiProject verifies that pixi can read and solve the environment's project. A
+// directory without pixi.toml passes, since the installation creates it.
+func checkPixiProject(envDir string) Check {
+	check := Check{Name: "pixi project"}
+	if _, err := os.Stat(filepath.Join(envDir, "pixi.toml")); err != nil {
+		check.Status = checkOK
+		check.Detail = fmt.Sprintf("%s will be created", envDir)
+		return check
+	}
+	if _, err := exec.LookPath("pixi"); err != nil {
+		check.Status = checkWarn
+		check.Detail = "pixi is not installed, so the project could not be checked"
+		return check
+	}
+
+	output, err := exec.Command("pixi", "list", "--manifest-path", envDir).CombinedOutput()
+	if err != nil {
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		check.Status = checkFail
+		check.Detail = fmt.Sprintf("pixi cannot load %s: %s", envDir, strings.TrimSpace(lines[0]))
+		check.Fix = fmt.Sprintf("fix %s, or rebuild the environment: rm -rf %s && pixi install --manifest-path %s",
+			filepath.Join(envDir, "pixi.toml"), filepath.Join(envDir, ".pixi"), envDir)
+		return check
+	}
+	check.Status = checkOK
+	check.Detail = envDir
+	return check
+}
+
+// versionMatches reports whether a version satisfies a simple conda spec such as 22.*, 3.12
+// or ==3.12.4; specs with other operators are not evaluated and always match
+func versionMatches(spec, version string) bool {
+	if strings.ContainsAny(spec, "<>!~,|") {
+		return true
+	}
+	if exact, found := strings.CutPrefix(spec, "=="); found {
+		return version == exact
+	}
+	spec = strings.TrimSuffix(strings.TrimPrefix(spec, "="), "*")
+	if strings.HasSuffix(spec, ".") {
+		return strings.HasPrefix(version, spec)
+	}
+	return version == spec || strings.HasPrefix(version, spec+".")
+}
+
+// checkCoreVersions verifies that Node.js and Python in the environment match the configured versions
+func checkCoreVersions(envDir string, core CoreConfig) []Check {
+	if _, err := os.Stat(filepath.Join(envDir, "pixi.toml")); err != nil {
+		return nil
+	}
+	if _, err := exec.LookPath("pixi"); err != nil {
+		return nil
+	}
+
+	runtimes := []struct {
+		command string
+		dep     CoreDependency
+	}{
+		{"node", CoreDependency{Name: "nodejs", Version: core.NodeVersion}},
+		{"python", CoreDependency{Name: "python", Version: core.PythonVersion}},
+	}
+	checks := []Check{}
+	for _, runtime := range runtimes {
+		check := Check{Name: CoreDependency{Name: runtime.dep.Name}.Label()}
+		output, err := exec.Command("pixi", "run", "--manifest-path", envDir, runtime.command, "--version").CombinedOutput()
+		version := versionPattern.FindString(string(output))
+		switch {
+		case err != nil || version == "":
+			check.Status = checkFail
+			check.Detail = fmt.Sprintf("%s is not available in %s", runtime.command, envDir)
+			check.Fix = fmt.Sprintf("pixi add --manifest-path %s %q", envDir, runtime.dep.Spec())
+		case !versionMatches(runtime.dep.Version, version):
+			check.Status = checkWarn
+			check.Detail = fmt.Sprintf("%s installed, %s configured", version, runtime.dep.Version)
+			check.Fix = fmt.Sprintf("pixi add --manifest-path %s %q, or press c on the welcome screen to change the configured version", envDir, runtime.dep.Spec())
+		default:
+			check.Status = checkOK
+			check.Detail = version
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// nearestExistingDir returns the path itself or its closest existing parent directory
+func nearestExistingDir(path string) string {
+	for {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// isWritableDir reports whether files can be created in a directory
+func isWritableDir(dir string) bool {
+	f, err := os.CreateTemp(dir, ".ai-menu-write-test-*")
+	if err != nil {
+		return false
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return true
+}
+
+// checkInstallPath verifies that the environment directory can be created or written to
+func checkInstallPath(envDir string) Check {
+	check := Check{Name: "install path"}
+	dir := nearestExistingDir(envDir)
+	if !isWritableDir(dir) {
+		check.Status = checkFail
+		check.Detail = fmt.Sprintf("%s is not writable", dir)
+		check.Fix = fmt.Sprintf("choose another install path, or run: sudo chown -R $(id -u):$(id -g) %s", dir)
+		return check
+	}
+	check.Status = checkOK
+	check.Detail = envDir
+	return check
+}
+
+// checkRCFiles verifies that the shell startup files receiving the managed block are writable
+func checkRCFiles(shells []string) []Check {
+	checks := []Check{}
+	for _, shell := range shells {
+		check := Check{Name: shell + " startup file"}
+		path, err := shellRCPath(shell)
+		if err != nil {
+			check.Status = checkFail
+			check.Detail = err.Error()
+			checks = append(checks, check)
+			continue
+		}
+
+		writable := false
+		if _, err := os.Stat(path); err == nil {
+			if f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0); err == nil {
+				f.Close()
+				writable = true
+			}
+		} else {
+			writable = isWritableDir(nearestExistingDir(filepath.Dir(path)))
+		}
+
+		if writable {
+			check.Status = checkOK
+			check.Detail = displayPath(path)
+		} else {
+			check.Status = checkFail
+			check.Detail = fmt.Sprintf("%s is not writable, so aliases cannot be installed", displayPath(path))
+			check.Fix = fmt.Sprintf("sudo chown $(id -u):$(id -g) %s", path)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkCatalog verifies that the built-in catalog entries are complete and consistent
+func checkCatalog() Check {
+	problems := []string{}
+	seen := make(map[string]bool)
+	for _, group := range catalogCategories {
+		for _, entry := range group.catalog {
+			if seen[entry.Name] {
+				problems = append(problems, fmt.Sprintf("%q is listed twice", entry.Name))
+			}
+			seen[entry.Name] = true
+
+			if entry.Package == "" {
+				problems = append(problems, fmt.Sprintf("%q has no package", entry.Name))
+			}
+			for _, platform := range entry.Platforms {
+				if !isKnownPlatform(platform) {
+					problems = append(problems, fmt.Sprintf("%q lists unknown platform %s", entry.Name, platform))
+				}
+			}
+			if entry.Completion != "" && !strings.Contains(entry.Completion, "{shell}") {
+				problems = append(problems, fmt.Sprintf("%q has a completion command without {shell}", entry.Name))
+			}
+			for _, name := range entry.Credentials {
+				if !credentialName.MatchString(name) {
+					problems = append(problems, fmt.Sprintf("%q has invalid credential variable %s", entry.Name, name))
+				}
+			}
+			if group.name == categoryCLI || group.name == categoryEnhancer {
+				// Unmapped packages such as @scope/name fall back to names that cannot be aliases
+				if !validAliasName.MatchString(getAliasName(entry.Package)) {
+					problems = append(problems, fmt.Sprintf("%q has no alias mapping", entry.Name))
+				}
+				if !validAliasName.MatchString(getCommandName(entry.Package)) {
+					problems = append(problems, fmt.Sprintf("%q has no command mapping", entry.Name))
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return Check{Name: "catalog", Status: checkFail, Detail: strings.Join(problems, "; "), Fix: "correct the entries in data.go and the alias/command maps in installer.go"}
+	}
+	return Check{Name: "catalog", Status: checkOK, Detail: fmt.Sprintf("%d entries", len(seen))}
+}
+
+// RunDoctor runs every diagnostic check against the active environment
+func RunDoctor() []Check {
+	checks := checkPrerequisites()
+	checks = append(checks, checkState()...)
+
+	cfg, _ := LoadConfig()
+	state, _ := LoadState()
+	if env := state.ActiveEnvironment(); env != nil && env.Exists() {
+		checks = append(checks, checkPixiProject(env.Path))
+		checks = append(checks, checkCoreVersions(env.Path, cfg.Core)...)
+		checks = append(checks, checkInstallPath(env.Path))
+	}
+	checks = append(checks, checkRCFiles(state.Shells())...)
+	return append(checks, checkCatalog())
+}
+
+// planPrerequisites returns the external commands the plan's installers run, all of them
+// required; commands only unselected tools need are left out
+func planPrerequisites(plan InstallPlan) []prerequisite {
+	needsCurl, needsSudo := false, false
+	for _, tool := range plan.CLITools {
+		if getInstallBackend(getPackageNameForCLI(tool)) == backendScript {
+			needsCurl = true
+		}
+	}
+	for _, tool := range plan.SpecialTools {
+		entry, _ := lookupCatalogEntry(tool)
+		switch specialToolBackend(entry.Package) {
+		case backendScript:
+			needsCurl = true
+			// lazygit is copied to /usr/local/bin
+			needsSudo = needsSudo || entry.Package == "lazygit"
+		case backendApt:
+			needsSudo = true
+			// gh adds its apt repository with curl first
+			needsCurl = needsCurl || entry.Package == "gh"
+		}
+	}
+
+	needed := []prerequisite{pixiPrerequisite}
+	for _, p := range []struct {
+		prerequisite
+		needed bool
+	}{
+		{curlPrerequisite, needsCurl},
+		{codePrerequisite, len(plan.VSCodeExtensions) > 0},
+		{sudoPrerequisite, needsSudo},
+	} {
+		if p.needed {
+			p.required = true
+			needed = append(needed, p.prerequisite)
+		}
+	}
+	return needed
+}
+
+// Preflight checks what an installation needs before anything is changed
+func Preflight(plan InstallPlan) []Check {
+	checks := []Check{}
+	for _, p := range planPrerequisites(plan) {
+		checks = append(checks, checkCommand(p))
+	}
+	checks = append(checks, checkInstallPath(plan.EnvDir()), checkEnvironmentName(plan.EnvName, plan.EnvDir()), checkPixiProject(plan.EnvDir()))
+	return append(checks, checkRCFiles(plan.ShellTargets)...)
+}
+
+// doctorPassed reports whether no check failed; warnings are allowed
+func doctorPassed(checks []Check) bool {
+	for _, check := range checks {
+		if check.Status == checkFail {
+			return false
+		}
+	}
+	return true
+}
+
+// unpassedChecks returns the checks that warned or failed
+func unpassedChecks(checks []Check) []Check {
+	unpassed := []Check{}
+	for _, check := range checks {
+		if check.Status != checkOK {
+			unpassed = append(unpassed, check)
+		}
+	}
+	return unpassed
+}
+
+// formatCheck renders a check as a line of text with its fix below it
+func formatCheck(check Check) string {
+	symbol := map[string]string{checkOK: "✓", checkWarn: "⚠️ ", checkFail: "✗"}[check.Status]
+	line := fmt.Sprintf("%s %s: %s", symbol, check.Name, check.Detail)
+	if check.Fix != "" {
+		line += "\n    fix: " + strings.ReplaceAll(check.Fix, "\n", "\n         ")
+	}
+	return line
+}
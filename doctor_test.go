@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionMatches(t *testing.T) {
+	tests := []struct {
+		spec, version string
+		want          bool
+	}{
+		{"22.*", "22.11.0", true},
+		{"22.*", "20.18.1", false},
+		{"22.*", "220.1.0", false},
+		{"=22.*", "22.1.0", true},
+		{"22.", "22.1.0", true},
+		{"3.12", "3.12.4", true},
+		{"3.12", "3.12", true},
+		{"3.12", "3.13.0", false},
+		{"3.1", "3.12.4", false},
+		// An exact pin only matches that version
+		{"==3.12.4", "3.12.4", true},
+		{"==3.12", "3.12.4", false},
+		// Ranges are not evaluated
+		{">=20", "18.0.0", true},
+		{"3.11|3.12", "3.10.0", true},
+	}
+	for _, tt := range tests {
+		if got := versionMatches(tt.spec, tt.version); got != tt.want {
+			t.Errorf("versionMatches(%q, %q) = %v, want %v", tt.spec, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestPlanPrerequisites(t *testing.T) {
+	tests := []struct {
+		name string
+		plan InstallPlan
+		want []string
+	}{
+		{"nothing selected", InstallPlan{}, []string{"pixi"}},
+		{"npm and uv tools", InstallPlan{CLITools: []string{"Codex by OpenAI", "Kimi by MoonshotAI"}, SpecialTools: []string{"modal - Serverless cloud platform CLI"}}, []string{"pixi"}},
+		{"vendor script", InstallPlan{CLITools: []string{"Goose"}}, []string{"pixi", "curl"}},
+		{"VS Code extension", InstallPlan{VSCodeExtensions: []string{"saoudrizwan.claude-dev - Cline"}}, []string{"pixi", "code"}},
+		{"apt package", InstallPlan{SpecialTools: []string{"jq - JSON processor"}}, []string{"pixi", "sudo"}},
+		// gh adds its apt repository with curl; lazygit is copied with sudo
+		{"gh", InstallPlan{SpecialTools: []string{"gh - GitHub CLI"}}, []string{"pixi", "curl", "sudo"}},
+		{"lazygit", InstallPlan{SpecialTools: []string{"lazygit - Git TUI"}}, []string{"pixi", "curl", "sudo"}},
+		{"helm", InstallPlan{SpecialTools: []string{"helm - Kubernetes package manager"}}, []string{"pixi", "curl"}},
+	}
+	for _, tt := range tests {
+		got := []string{}
+		for _, p := range planPrerequisites(tt.plan) {
+			got = append(got, p.command)
+			if !p.required {
+				t.Errorf("%s: %s is not required", tt.name, p.command)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: planPrerequisites = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Selecting a tool does not change the shared prerequisite definitions
+	if curlPrerequisite.required {
+		t.Error("planPrerequisites modified curlPrerequisite")
+	}
+}
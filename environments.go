@@ -0,0 +1,317 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultEnvName is the environment directory created when no other name is chosen
+const defaultEnvName = "ai-dev-pixi"
+
+// State records the environments managed by ai-menu and the tools installed in each
+type State struct {
+	Active        string              `json:"active,omitempty"`
+	ShellTargets  []string            `json:"shell_targets,omitempty"`
+	Mode          string              `json:"mode,omitempty"`
+	GlobalAliases map[string]string   `json:"global_aliases,omitempty"`
+	AliasNames    AliasNames          `json:"alias_names,omitempty"`
+	Environments  []EnvironmentRecord `json:"environments"`
+}
+
+// EnvironmentRecord describes a single named pixi environment
+type EnvironmentRecord struct {
+	Name      string       `json:"name"`
+	Path      string       `json:"path"`
+	Tools     []ToolRecord `json:"tools,omitempty"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// ToolRecord describes an installed tool and the alias that runs it
+type ToolRecord struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Alias    string `json:"alias"`
+	Command  string `json:"command"`
+	PixiEnv  string `json:"pixi_env,omitempty"`
+	Version  string `json:"version,omitempty"`
+}
+
+// AliasNames maps default alias names to the names chosen by the user; an empty name disables the alias
+type AliasNames map[string]string
+
+// Lookup returns the effective name of a default alias and whether it is enabled
+func (a AliasNames) Lookup(name string) (string, bool) {
+	custom, exists := a[name]
+	if !exists {
+		return name, true
+	}
+	return custom, custom != ""
+}
+
+// Set renames a default alias; an empty name disables it and the default name restores it
+func (a AliasNames) Set(name, custom string) {
+	if custom == name {
+		delete(a, name)
+		return
+	}
+	a[name] = custom
+}
+
+// Clone returns an independent copy that can be edited without touching the original
+func (a AliasNames) Clone() AliasNames {
+	clone := make(AliasNames, len(a))
+	for name, custom := range a {
+		clone[name] = custom
+	}
+	return clone
+}
+
+// Tool categories recorded in the state file
+const (
+	categoryCLI      = "cli"
+	categoryEnhancer = "enhancer"
+	categorySpecial  = "special"
+	categoryVSCode   = "vscode"
+)
+
+// envDirFor returns the directory of the named environment inside the parent path
+func envDirFor(installPath, envName string) string {
+	if envName == "" {
+		envName = defaultEnvName
+	}
+	return filepath.Join(installPath, envName)
+}
+
+// validateEnvName rejects names that cannot be used as a single directory component
+func validateEnvName(name string) error {
+	if name == "" {
+		return fmt.Errorf("environment name cannot be empty")
+	}
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid environment name %q", name)
+	}
+	return nil
+}
+
+// statePath returns the location of the ai-menu state file
+func statePath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "state.json"), nil
+}
+
+// LoadState reads the state file, returning an empty state if it does not exist
+func LoadState() (*State, error) {
+	state := &State{Environments: []EnvironmentRecord{}}
+
+	path, err := statePath()
+	if err != nil {
+		return state, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return &State{Environments: []EnvironmentRecord{}}, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// Save writes the state file
+func (s *State) Save() error {
+	path, err := statePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write a temporary file and rename it over the state, so an interrupted save never
+	// loses the record of the installed environments
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".state.json-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Environment returns the named environment, or nil if it is unknown
+func (s *State) Environment(name string) *EnvironmentRecord {
+	for i := range s.Environments {
+		if s.Environments[i].Name == name {
+			return &s.Environments[i]
+		}
+	}
+	return nil
+}
+
+// ActiveEnvironment returns the environment whose aliases are currently active
+func (s *State) ActiveEnvironment() *EnvironmentRecord {
+	return s.Environment(s.Active)
+}
+
+// Shells returns the shells that receive aliases, defaulting to the login shell
+func (s *State) Shells() []string {
+	if len(s.ShellTargets) == 0 {
+		return defaultShellTargets()
+	}
+	return s.ShellTargets
+}
+
+// IntegrationMode returns how tools are exposed to the shell: aliases or shims on PATH
+func (s *State) IntegrationMode() string {
+	if s.Mode == modeShims {
+		return modeShims
+	}
+	return modeAliases
+}
+
+// SetGlobalAlias records an alias that does not depend on the active environment
+func (s *State) SetGlobalAlias(name, command string) {
+	if s.GlobalAliases == nil {
+		s.GlobalAliases = make(map[string]string)
+	}
+	s.GlobalAliases[name] = command
+}
+
+// EnvironmentAt returns the environment registered at the given directory, or nil
+func (s *State) EnvironmentAt(path string) *EnvironmentRecord {
+	for i := range s.Environments {
+		if filepath.Clean(s.Environments[i].Path) == filepath.Clean(path) {
+			return &s.Environments[i]
+		}
+	}
+	return nil
+}
+
+// UpsertEnvironment registers an environment, or refreshes it if it already exists. A chosen
+// name that is registered at another path is rejected rather than taking over that record. The
+// default name is used once per project, so a default environment at another path is
+// registered under a name derived from its parent directory.
+func (s *State) UpsertEnvironment(name, path string) (*EnvironmentRecord, error) {
+	if env := s.EnvironmentAt(path); env != nil && (env.Name == name || name == defaultEnvName) {
+		env.UpdatedAt = time.Now()
+		return env, nil
+	}
+	if env := s.Environment(name); env != nil {
+		if name != defaultEnvName {
+			return nil, fmt.Errorf("environment %q is already registered at %s", name, env.Path)
+		}
+		name = s.uniqueEnvName(path)
+	}
+
+	s.Environments = append(s.Environments, EnvironmentRecord{
+		Name:      name,
+		Path:      path,
+		UpdatedAt: time.Now(),
+	})
+	sort.Slice(s.Environments, func(i, j int) bool {
+		return s.Environments[i].Name < s.Environments[j].Name
+	})
+	return s.Environment(name), nil
+}
+
+// uniqueEnvName derives an unused name for a default environment from its parent directory,
+// such as ai-dev-pixi-myproject, adding a number if that is taken too
+func (s *State) uniqueEnvName(path string) string {
+	base := defaultEnvName + "-" + filepath.Base(filepath.Dir(filepath.Clean(path)))
+	if validateEnvName(base) != nil {
+		base = defaultEnvName
+	}
+	name := base
+	for i := 2; s.Environment(name) != nil; i++ {
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	return name
+}
+
+// RecordTools adds or replaces tool records in the environment, keyed by alias
+func (e *EnvironmentRecord) RecordTools(tools []ToolRecord) {
+	for _, tool := range tools {
+		replaced := false
+		for i := range e.Tools {
+			if e.Tools[i].Alias == tool.Alias {
+				e.Tools[i] = tool
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			e.Tools = append(e.Tools, tool)
+		}
+	}
+	e.UpdatedAt = time.Now()
+}
+
+// RemoveTool drops the record of the tool with the given alias
+func (e *EnvironmentRecord) RemoveTool(alias string) {
+	kept := e.Tools[:0]
+	for _, tool := range e.Tools {
+		if tool.Alias != alias {
+			kept = append(kept, tool)
+		}
+	}
+	e.Tools = kept
+	e.UpdatedAt = time.Now()
+}
+
+// FindTool returns the recorded tool known by the given alias, package or command name
+func (e *EnvironmentRecord) FindTool(name string) (ToolRecord, bool) {
+	for _, tool := range e.Tools {
+		if tool.Alias == name || tool.Name == name || tool.Command == name {
+			return tool, true
+		}
+	}
+	return ToolRecord{}, false
+}
+
+// Exists reports whether the environment directory contains a pixi manifest
+func (e EnvironmentRecord) Exists() bool {
+	_, err := os.Stat(filepath.Join(e.Path, "pixi.toml"))
+	return err == nil
+}
+
+// discoverEnvironments returns the known environments plus an unregistered
+// default environment in the install path, so pre-existing installs are listed
+func discoverEnvironments(state *State, installPath string) []EnvironmentRecord {
+	envs := append([]EnvironmentRecord{}, state.Environments...)
+
+	legacy := EnvironmentRecord{Name: defaultEnvName, Path: envDirFor(installPath, defaultEnvName)}
+	if state.EnvironmentAt(legacy.Path) == nil && legacy.Exists() {
+		envs = append(envs, legacy)
+	}
+	return envs
+}
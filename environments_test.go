@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUpsertEnvironment(t *testing.T) {
+	tests := []struct {
+		name      string
+		envName   string
+		path      string
+		wantName  string
+		wantErr   bool
+		wantPaths map[string]string
+	}{
+		{"new environment", "other", "/work/other", "other", false, map[string]string{"other": "/work/other"}},
+		{"same name and path", "team", "/work/team", "team", false, nil},
+		{"same path written differently", "team", "/work/./team/", "team", false, nil},
+		{"same name at another path", "team", "/elsewhere/team", "", true, nil},
+		{"default name and path", defaultEnvName, "/work/ai-dev-pixi", defaultEnvName, false, nil},
+		// Every project can use the default name
+		{"default name in another project", defaultEnvName, "/projects/site/ai-dev-pixi", "ai-dev-pixi-site", false,
+			map[string]string{"ai-dev-pixi-site": "/projects/site/ai-dev-pixi"}},
+		{"derived name taken too", defaultEnvName, "/projects/api/ai-dev-pixi", "ai-dev-pixi-api-2", false,
+			map[string]string{"ai-dev-pixi-api-2": "/projects/api/ai-dev-pixi"}},
+		{"default environment registered under a derived name", defaultEnvName, "/other/api/ai-dev-pixi", "ai-dev-pixi-api", false, nil},
+		{"default name at the root", defaultEnvName, "/ai-dev-pixi", "ai-dev-pixi-2", false,
+			map[string]string{"ai-dev-pixi-2": "/ai-dev-pixi"}},
+	}
+	for _, tt := range tests {
+		state := &State{Environments: []EnvironmentRecord{
+			{Name: "team", Path: "/work/team", Tools: []ToolRecord{{Name: "jq"}}},
+			{Name: defaultEnvName, Path: "/work/ai-dev-pixi"},
+			{Name: "ai-dev-pixi-api", Path: "/other/api/ai-dev-pixi"},
+		}}
+		env, err := state.UpsertEnvironment(tt.envName, tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && env.Name != tt.wantName {
+			t.Errorf("%s: returned %q, want %q", tt.name, env.Name, tt.wantName)
+		}
+		// The existing records are kept as they were
+		wantPaths := map[string]string{"team": "/work/team", defaultEnvName: "/work/ai-dev-pixi", "ai-dev-pixi-api": "/other/api/ai-dev-pixi"}
+		for name, path := range tt.wantPaths {
+			wantPaths[name] = path
+		}
+		if len(state.Environments) != len(wantPaths) {
+			t.Errorf("%s: %d environments, want %d", tt.name, len(state.Environments), len(wantPaths))
+		}
+		for name, path := range wantPaths {
+			if env := state.Environment(name); env == nil || env.Path != path {
+				t.Errorf("%s: environment %q = %+v, want path %s", tt.name, name, env, path)
+			}
+		}
+		// The existing record keeps its tools
+		if env := state.Environment("team"); len(env.Tools) != 1 {
+			t.Errorf("%s: team has %d tools, want 1", tt.name, len(env.Tools))
+		}
+	}
+}
+
+func TestStateSave(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	state := &State{Active: "team", Environments: []EnvironmentRecord{{Name: "team", Path: "/work/team", Tools: []ToolRecord{{Name: "jq", Category: categorySpecial}}}}}
+	for _, active := range []string{"team", ""} {
+		state.Active = active
+		if err := state.Save(); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded.Active != state.Active || !reflect.DeepEqual(loaded.Environments[0].Tools, state.Environments[0].Tools) {
+			t.Errorf("loaded %+v, want %+v", loaded, state)
+		}
+	}
+
+	// Only state.json is left; no temporary file
+	entries, err := os.ReadDir(filepath.Join(dir, "ai-menu"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "state.json" {
+		t.Errorf("config directory contains %v, want only state.json", entries)
+	}
+	info, err := os.Stat(filepath.Join(dir, "ai-menu", "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("state.json mode = %v, want 0644", info.Mode().Perm())
+	}
+}
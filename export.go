@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// devcontainerCommonUtils is the devcontainer feature that provides curl, sudo and the extra shells
+const devcontainerCommonUtils = "ghcr.io/devcontainers/features/common-utils:2"
+
+// pixiInstallCommand installs pixi when it is missing; the installer puts it in ~/.pixi/bin
+const pixiInstallCommand = `{ command -v pixi >/dev/null 2>&1 || curl -fsSL https://pixi.sh/install.sh | bash; } && export PATH="$HOME/.pixi/bin:$PATH"`
+
+// aiMenuInstallCommand installs the latest ai-menu release with scripts/install.sh, which
+// names the binary aimenu, into ~/.local/bin
+const aiMenuInstallCommand = `curl -fsSL https://raw.githubusercontent.com/smpnet74/ai-menu/main/scripts/install.sh | INSTALL_DIR="$HOME/.local/bin" bash && export PATH="$HOME/.local/bin:$PATH"`
+
+// shortName returns the name a catalog item is given on the command line, e.g. codex or jq
+func shortName(item string) string {
+	entry, ok := lookupCatalogEntry(item)
+	if !ok {
+		return item
+	}
+	// Packages without an alias mapping, such as jq or VS Code extension IDs, are their own name
+	return getAliasName(entry.Package)
+}
+
+// shortNames maps catalog items to their command-line names
+func shortNames(items []string) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, shortName(item))
+	}
+	return names
+}
+
+// installArgs returns the ai-menu arguments that install the plan non-interactively. The
+// path is left out when the installation should go to the working directory.
+func installArgs(plan InstallPlan, includePath bool) []string {
+	args := []string{"install"}
+	for _, selection := range []struct {
+		flag  string
+		items []string
+	}{
+		{"--cli", plan.CLITools},
+		{"--vscode", plan.VSCodeExtensions},
+		{"--special", plan.SpecialTools},
+		{"--enhancers", plan.CLIEnhancers},
+	} {
+		if len(selection.items) > 0 {
+			args = append(args, selection.flag, strings.Join(shortNames(selection.items), ","))
+		}
+	}
+
+	if includePath {
+		args = append(args, "--path", plan.InstallPath)
+	}
+	if plan.EnvName != defaultEnvName {
+		args = append(args, "--env", plan.EnvName)
+	}
+	if len(plan.ShellTargets) > 0 {
+		args = append(args, "--shell", strings.Join(plan.ShellTargets, ","))
+	}
+	if plan.Mode == modeShims {
+		args = append(args, "--shims")
+	}
+	if plan.Isolate {
+		args = append(args, "--isolate")
+	}
+	if len(plan.CredentialVars()) > 0 {
+		args = append(args, "--save-credentials")
+	}
+	return append(args, "--yes")
+}
+
+// shellQuote quotes a word for sh when it contains anything but safe characters
+func shellQuote(word string) string {
+	safe := word != "" && strings.IndexFunc(word, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./,:=@+", r))
+	}) < 0
+	if safe {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// shellCommand joins words into a command line
+func shellCommand(words ...string) string {
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		quoted = append(quoted, shellQuote(word))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// devcontainerFragment returns the devcontainer.json properties that rebuild the plan's
+// installation in a new container
+func devcontainerFragment(plan InstallPlan, includePath bool) map[string]interface{} {
+	fragment := make(map[string]interface{})
+
+	// VS Code installs the extensions itself from customizations, where the code CLI may be missing
+	postCreate := plan
+	postCreate.VSCodeExtensions = nil
+
+	// common-utils provides curl and sudo, and installs the extra shells that get aliases
+	features := make(map[string]interface{})
+	utils := make(map[string]interface{})
+	for _, p := range planPrerequisites(postCreate) {
+		if p.command == "curl" || p.command == "sudo" {
+			features[devcontainerCommonUtils] = utils
+		}
+	}
+	if containsString(plan.ShellTargets, shellZsh) {
+		utils["installZsh"] = true
+		features[devcontainerCommonUtils] = utils
+	}
+	if len(features) > 0 {
+		fragment["features"] = features
+	}
+
+	fragment["postCreateCommand"] = pixiInstallCommand + " && " + aiMenuInstallCommand + " && " + shellCommand(append([]string{releaseAssetPrefix}, installArgs(postCreate, includePath)...)...)
+
+	if len(plan.VSCodeExtensions) > 0 {
+		extensions := make([]string, 0, len(plan.VSCodeExtensions))
+		for _, item := range plan.VSCodeExtensions {
+			entry, _ := lookupCatalogEntry(item)
+			extensions = append(extensions, entry.Package)
+		}
+		fragment["customizations"] = map[string]interface{}{
+			"vscode": map[string]interface{}{"extensions": extensions},
+		}
+	}
+
+	// Forward the API keys from the host instead of baking them into the image
+	if vars := plan.CredentialVars(); len(vars) > 0 {
+		remoteEnv := make(map[string]string, len(vars))
+		for _, name := range vars {
+			remoteEnv[name] = "${localEnv:" + name + "}"
+		}
+		fragment["remoteEnv"] = remoteEnv
+	}
+	return fragment
+}
+
+// writeExport writes generated content to a file, or to out when path is empty or "-"
+func writeExport(path string, content []byte, mode os.FileMode, out io.Writer) error {
+	if path == "" || path == "-" {
+		_, err := out.Write(content)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, mode); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✓ Wrote %s\n", path)
+	return nil
+}
+
+// exportUsage describes the export formats
+const exportUsage = `Usage: ai-menu export <format> [selection flags] [--output file]
+
+Formats:
+  devcontainer   devcontainer.json properties that rerun the installation on every rebuild
+  dockerfile     a Dockerfile that bakes the installation into an image (--base sets the image)
+  script         a bash script that performs the installation without ai-menu
+  pixi           a standalone pixi.toml with setup tasks; --output <dir> also writes pixi.lock
+
+The selection flags are those of 'ai-menu install', e.g. --profile team or --cli codex.
+`
+
+// runExport generates files that reproduce an installation elsewhere
+func runExport(args []string, out io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(os.Stderr, exportUsage)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	format := args[0]
+	fs := flag.NewFlagSet("export "+format, flag.ContinueOnError)
+	selection := addInstallFlags(fs)
+	output := fs.String("output", "", "file to write (default: stdout)")
+	baseImage := fs.String("base", defaultBaseImage, "base image of the Dockerfile")
+	if code, stop := parseCommandFlags(fs, args[1:]); stop {
+		return code
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+	}
+	state, err := LoadState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+	}
+	plan, err := selection.plan(cfg, state)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitUsage
+	}
+
+	// A path chosen by flag or profile is kept; otherwise the export installs into the working directory
+	defaultPath, _ := filepath.Abs(*selection.path)
+	includePath := selection.isSet("path") || plan.InstallPath != defaultPath
+
+	var content []byte
+	mode := os.FileMode(0644)
+	switch format {
+	case "devcontainer":
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(devcontainerFragment(plan, includePath))
+		content = buf.Bytes()
+	case "dockerfile":
+		if !includePath {
+			plan.InstallPath = containerInstallPath
+		}
+		content = []byte(renderDockerfile(plan, *baseImage))
+	case "pixi":
+		if *output != "" && *output != "-" {
+			if err := writePixiProject(*output, plan); err != nil {
+				fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+				return exitFailed
+			}
+			return exitOK
+		}
+		content = []byte(renderPixiManifest(plan))
+	case "script":
+		content = []byte(renderBuildScript(plan))
+		mode = 0755
+	default:
+		fmt.Fprintf(os.Stderr, "ai-menu: unknown export format %q\n\n%s", format, exportUsage)
+		return exitUsage
+	}
+
+	if err := writeExport(*output, content, mode, out); err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitFailed
+	}
+	return exitOK
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDevcontainerFragment(t *testing.T) {
+	plan := InstallPlan{
+		InstallPath:      "/work/${USER}/pro$ject {x}",
+		EnvName:          "team",
+		CLITools:         []string{"Codex by OpenAI", "Gemini CLI by Google"},
+		VSCodeExtensions: []string{"saoudrizwan.claude-dev - Cline"},
+		SpecialTools:     []string{"modal - Serverless cloud platform CLI"},
+		ShellTargets:     []string{shellBash, shellZsh},
+	}
+	fragment := devcontainerFragment(plan, true)
+
+	// The install command follows the pixi and ai-menu installers
+	command, ok := fragment["postCreateCommand"].(string)
+	if !ok {
+		t.Fatalf("postCreateCommand = %#v", fragment["postCreateCommand"])
+	}
+	prefix := pixiInstallCommand + " && " + aiMenuInstallCommand + " && "
+	if !strings.HasPrefix(command, prefix) {
+		t.Fatalf("postCreateCommand = %q, want it to start with %q", command, prefix)
+	}
+	install := strings.TrimPrefix(command, prefix)
+
+	// The shell must pass the path through untouched, without expanding $ or braces
+	wantArgs := []string{releaseAssetPrefix, "install", "--cli", "codex,gemini", "--special", "modal",
+		"--path", plan.InstallPath, "--env", "team", "--shell", "bash,zsh", "--save-credentials", "--yes"}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	out, err := exec.Command("sh", "-c", "set -- "+install+`; printf '%s\n' "$@"`).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n"); !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("sh reads the install command as %q, want %q", args, wantArgs)
+	}
+
+	// Extensions are installed by VS Code, not by ai-menu in the container
+	wantCustomizations := map[string]interface{}{
+		"vscode": map[string]interface{}{"extensions": []string{"saoudrizwan.claude-dev"}},
+	}
+	if !reflect.DeepEqual(fragment["customizations"], wantCustomizations) {
+		t.Errorf("customizations = %#v, want %#v", fragment["customizations"], wantCustomizations)
+	}
+
+	// API keys are forwarded from the host
+	wantEnv := map[string]string{
+		"OPENAI_API_KEY":     "${localEnv:OPENAI_API_KEY}",
+		"GEMINI_API_KEY":     "${localEnv:GEMINI_API_KEY}",
+		"MODAL_TOKEN_ID":     "${localEnv:MODAL_TOKEN_ID}",
+		"MODAL_TOKEN_SECRET": "${localEnv:MODAL_TOKEN_SECRET}",
+	}
+	if !reflect.DeepEqual(fragment["remoteEnv"], wantEnv) {
+		t.Errorf("remoteEnv = %#v, want %#v", fragment["remoteEnv"], wantEnv)
+	}
+
+	// zsh comes from common-utils
+	features, _ := fragment["features"].(map[string]interface{})
+	if utils, _ := features[devcontainerCommonUtils].(map[string]interface{}); utils["installZsh"] != true {
+		t.Errorf("features = %#v, want common-utils with installZsh", fragment["features"])
+	}
+}
+
+func TestDevcontainerFragmentMinimal(t *testing.T) {
+	// An npm tool without API keys needs no features, customizations or remote environment
+	plan := InstallPlan{InstallPath: "/work", EnvName: defaultEnvName, CLITools: []string{"Forgecode"}}
+	fragment := devcontainerFragment(plan, false)
+	for _, key := range []string{"features", "customizations", "remoteEnv"} {
+		if value, ok := fragment[key]; ok {
+			t.Errorf("fragment has %s: %#v", key, value)
+		}
+	}
+	want := pixiInstallCommand + " && " + aiMenuInstallCommand + " && " + releaseAssetPrefix + " install --cli forge --yes"
+	if command := fragment["postCreateCommand"]; command != want {
+		t.Errorf("postCreateCommand = %q, want %q", command, want)
+	}
+}
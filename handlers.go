@@ -1,54 +1,417 @@
 package main
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func (m model) handleEnter() (tea.Model, tea.Cmd) {
 	switch m.state {
-	case welcomeView:
-		m.state = cliToolsView
-		m.cursor = 0
-	case cliToolsView:
-		m.state = vscodeExtensionsView
-		m.cursor = 0
-	case vscodeExtensionsView:
-		m.state = specialToolsView
+	case profilesView:
+		if m.cursor < len(m.profiles) {
+			m.applyProfile(m.profiles[m.cursor])
+		}
+		m.state = welcomeView
 		m.cursor = 0
-	case specialToolsView:
-		m.state = cliEnhancersView
+	case welcomeView, cliToolsView, vscodeExtensionsView, specialToolsView, cliEnhancersView:
+		m.clearFilter()
+		m.state = m.nextSelectionView(m.state)
+		if m.state == pathInputView {
+			// ALWAYS go to path input because the configured core dependencies
+			// are guaranteed to be installed regardless of selections
+			return m, m.focusPathInput(0)
+		}
+	case pathInputView:
 		m.cursor = 0
-	case cliEnhancersView:
-		// ALWAYS go to path input because core dependencies (Node 22 and Python 3.12)
-		// are guaranteed to be installed regardless of selections
-		m.state = pathInputView
-		m.pathInput.Focus()
+		return m, m.enterCredentials()
+	case shellTargetsView:
+		// At least one shell must receive the aliases
+		if len(m.shellTargets) == 0 {
+			return m, nil
+		}
+		m.aliasList = m.plannedAliases()
+		m.refreshAliasConflicts()
+		m.state = aliasNamesView
 		m.cursor = 0
-	case pathInputView:
+	case aliasNamesView:
+		m.preflight = Preflight(m.installPlan())
 		m.state = installView
 		m.cursor = 0
 	case installView:
+		// Check again in case the problems were fixed meanwhile, and only start when nothing fails
+		m.preflight = Preflight(m.installPlan())
+		if !doctorPassed(m.preflight) {
+			return m, nil
+		}
 		// Trigger installation
 		return m, func() tea.Msg { return installMsgStart{} }
 	}
 	return m, nil
 }
 
+// focusCoreInput moves focus to the given core configuration field
+func (m *model) focusCoreInput(index int) tea.Cmd {
+	m.coreFocus = index
+	for i := range m.coreInputs {
+		if i == index {
+			continue
+		}
+		m.coreInputs[i].Blur()
+	}
+	return m.coreInputs[index].Focus()
+}
+
+// saveCoreConfig applies the core configuration inputs and writes them to config.toml
+func (m model) saveCoreConfig() (tea.Model, tea.Cmd) {
+	core := CoreConfig{
+		NodeVersion:   m.coreInputs[coreNodeField].Value(),
+		PythonVersion: m.coreInputs[corePythonField].Value(),
+		ExtraPackages: parseCommaList(m.coreInputs[coreExtrasField].Value()),
+		Platforms:     parseCommaList(m.coreInputs[corePlatformsField].Value()),
+	}.normalized()
+
+	// Keep the screen open until the platform list is usable on this machine
+	if err := validatePlatforms(core.Platforms, m.platform); err != nil {
+		m.coreErr = err
+		return m, nil
+	}
+	m.coreErr = nil
+
+	m.config.Core = core
+
+	// Save over the file's own settings so AI_MENU_* overrides are not written to it
+	saved, err := loadConfigFile()
+	if err == nil {
+		saved.Core = core
+		err = SaveConfig(saved)
+	}
+	m.err = err
+	m.coreInputs = newCoreInputs(core)
+	m.state = welcomeView
+	return m, nil
+}
+
+// nextSelectionView returns the first enabled selection screen after the given view, or the
+// path input after the last one
+func (m model) nextSelectionView(after sessionState) sessionState {
+	passed := after == welcomeView
+	for _, screen := range selectionScreens {
+		if passed && m.config.CategoryEnabled(screen.category) {
+			return screen.view
+		}
+		passed = passed || screen.view == after
+	}
+	return pathInputView
+}
+
+// previousSelectionView returns the last enabled selection screen before the given view, or
+// the welcome screen before the first one
+func (m model) previousSelectionView(before sessionState) sessionState {
+	passed := before == pathInputView
+	for i := len(selectionScreens) - 1; i >= 0; i-- {
+		screen := selectionScreens[i]
+		if passed && m.config.CategoryEnabled(screen.category) {
+			return screen.view
+		}
+		passed = passed || screen.view == before
+	}
+	return welcomeView
+}
+
+// clearFilter empties and closes the selection filter
+func (m *model) clearFilter() {
+	m.filterInput.SetValue("")
+	m.filterInput.Blur()
+	m.filtering = false
+	m.cursor = 0
+}
+
+// focusPathInput moves focus between the parent directory (0) and environment name (1) fields
+func (m *model) focusPathInput(index int) tea.Cmd {
+	m.pathFocus = index
+	if index == 0 {
+		m.envInput.Blur()
+		return m.pathInput.Focus()
+	}
+	m.pathInput.Blur()
+	return m.envInput.Focus()
+}
+
+// selectedCatalogItems returns the selected tools of every category, in catalog order
+func (m model) selectedCatalogItems() []string {
+	items := []string{}
+	for _, group := range []struct {
+		names    []string
+		selected map[string]bool
+	}{
+		{m.cliTools, m.selectedCLI},
+		{m.vscodeExts, m.selectedVSCode},
+		{m.specialTools, m.selectedSpecial},
+		{m.cliEnhancers, m.selectedCLIEnhancers},
+	} {
+		for _, name := range group.names {
+			if group.selected[name] {
+				items = append(items, name)
+			}
+		}
+	}
+	return items
+}
+
+// enterCredentials opens the credentials step for the selected tools, or skips it when none
+// of them needs an API key. Values already typed are kept when the step is revisited.
+func (m *model) enterCredentials() tea.Cmd {
+	previous := make(map[string]string)
+	for i, name := range m.credentialVars {
+		previous[name] = m.credentialInputs[i].Value()
+	}
+
+	m.credentialVars = credentialVarsFor(m.selectedCatalogItems())
+	if len(m.credentialVars) == 0 {
+		m.state = shellTargetsView
+		return nil
+	}
+
+	saved, err := LoadCredentials(envDirFor(m.installPath, m.envName))
+	if err != nil {
+		m.err = err
+	}
+	m.savedCredentials = saved
+
+	m.credentialInputs = make([]textinput.Model, len(m.credentialVars))
+	for i, name := range m.credentialVars {
+		input := textinput.New()
+		input.EchoMode = textinput.EchoPassword
+		input.EchoCharacter = '•'
+		input.CharLimit = 512
+		input.Width = 50
+		input.Placeholder = "leave empty to keep the current value"
+		input.SetValue(previous[name])
+		m.credentialInputs[i] = input
+	}
+
+	m.state = credentialsView
+	return m.focusCredentialInput(0)
+}
+
+// focusCredentialInput moves focus to the given credential field
+func (m *model) focusCredentialInput(index int) tea.Cmd {
+	m.credentialFocus = index
+	for i := range m.credentialInputs {
+		if i != index {
+			m.credentialInputs[i].Blur()
+		}
+	}
+	return m.credentialInputs[index].Focus()
+}
+
+// enteredCredentials returns the credential values typed in the credentials step
+func (m model) enteredCredentials() map[string]string {
+	values := make(map[string]string)
+	for i, name := range m.credentialVars {
+		if value := strings.TrimSpace(m.credentialInputs[i].Value()); value != "" {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// credentialUsers returns the selected tools that need the environment variable
+func (m model) credentialUsers(name string) []string {
+	users := []string{}
+	for _, item := range m.selectedCatalogItems() {
+		if entry, ok := lookupCatalogEntry(item); ok && containsString(entry.Credentials, name) {
+			users = append(users, strings.TrimSpace(strings.Split(item, " - ")[0]))
+		}
+	}
+	return users
+}
+
+// openProfiles loads the presets and saved profiles and shows the profile list
+func (m *model) openProfiles() {
+	profiles, err := LoadProfiles()
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("⚠️  %v", err)
+	}
+	m.profiles = profiles
+	m.state = profilesView
+	m.cursor = 0
+}
+
+// applyProfile replaces the current selections, path and environment name with the profile's
+func (m *model) applyProfile(profile Profile) {
+	cli, vscode, special, enhancers, skipped := profile.withoutDisabled(m.config).resolve(m.platform)
+	m.selectedCLI = selectionSet(cli)
+	m.selectedVSCode = selectionSet(vscode)
+	m.selectedSpecial = selectionSet(special)
+	m.selectedCLIEnhancers = selectionSet(enhancers)
+
+	if profile.InstallPath != "" {
+		m.installPath = profile.InstallPath
+		m.pathInput.SetValue(profile.InstallPath)
+	}
+	if profile.EnvName != "" {
+		m.envName = profile.EnvName
+		m.envInput.SetValue(profile.EnvName)
+	}
+
+	count := len(cli) + len(vscode) + len(special) + len(enhancers)
+	m.statusMessage = fmt.Sprintf("✓ Loaded profile %s (%d item(s)); press enter to review the selections", profile.Name, count)
+	if len(skipped) > 0 {
+		m.statusMessage += fmt.Sprintf("\n⚠️  Skipped items unknown or unavailable here: %s", strings.Join(skipped, ", "))
+	}
+}
+
+// selectionSet builds a selection map from a list of items
+func selectionSet(items []string) map[string]bool {
+	selected := make(map[string]bool, len(items))
+	for _, item := range items {
+		selected[item] = true
+	}
+	return selected
+}
+
+// saveProfile stores the current selections, path and environment name under the typed name
+func (m *model) saveProfile() {
+	name := strings.TrimSpace(m.profileInput.Value())
+	if name == "" {
+		m.profileMessage = "✗ Enter a name for the profile"
+		return
+	}
+
+	selection := func(names []string, selected map[string]bool) []string {
+		items := []string{}
+		for _, name := range names {
+			if selected[name] {
+				items = append(items, name)
+			}
+		}
+		return items
+	}
+
+	profile := Profile{
+		Name:             name,
+		CLITools:         selection(m.cliTools, m.selectedCLI),
+		VSCodeExtensions: selection(m.vscodeExts, m.selectedVSCode),
+		SpecialTools:     selection(m.specialTools, m.selectedSpecial),
+		CLIEnhancers:     selection(m.cliEnhancers, m.selectedCLIEnhancers),
+		InstallPath:      m.installPath,
+		EnvName:          m.envName,
+	}
+	if err := SaveProfile(profile); err != nil {
+		m.profileMessage = fmt.Sprintf("✗ Could not save profile: %v", err)
+		return
+	}
+
+	m.savingProfile = false
+	m.profileInput.Blur()
+	m.profileMessage = fmt.Sprintf("✓ Saved profile %s; load it with p on the welcome screen or --profile %q", name, name)
+}
+
+// selectExistingEnvironment fills the path step with the next or previous known environment
+func (m *model) selectExistingEnvironment(forward bool) {
+	if len(m.environments) == 0 {
+		return
+	}
+
+	// Find the environment currently shown in the inputs
+	current := -1
+	target := envDirFor(m.pathInput.Value(), m.envInput.Value())
+	for i, env := range m.environments {
+		if env.Path == target {
+			current = i
+			break
+		}
+	}
+
+	next := 0
+	if current >= 0 {
+		if forward {
+			next = (current + 1) % len(m.environments)
+		} else {
+			next = (current + len(m.environments) - 1) % len(m.environments)
+		}
+	}
+
+	env := m.environments[next]
+	m.pathInput.SetValue(filepath.Dir(env.Path))
+	m.envInput.SetValue(env.Name)
+	m.pathErr = nil
+}
+
+// switchEnvironment activates the aliases of the environment under the welcome view cursor
+func (m *model) switchEnvironment() {
+	if m.cursor >= len(m.environments) {
+		return
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("✗ Could not load state: %v", err)
+		return
+	}
+
+	selected := m.environments[m.cursor]
+	env, err := state.UpsertEnvironment(selected.Name, selected.Path)
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("✗ Could not switch to %s: %v", selected.Name, err)
+		return
+	}
+	if err := ActivateEnvironment(state, env.Name, func(msg string) { m.statusMessage = msg }); err != nil {
+		m.statusMessage = fmt.Sprintf("✗ Could not switch to %s: %v", selected.Name, err)
+		return
+	}
+
+	m.activeEnv = state.Active
+	m.environments = discoverEnvironments(state, m.installPath)
+}
+
+// removeShellIntegration deletes the managed alias block after a confirming second key press
+func (m *model) removeShellIntegration() {
+	if !m.confirmRemove {
+		m.confirmRemove = true
+		m.statusMessage = "Press x again to remove all ai-menu aliases from your shell startup files"
+		return
+	}
+	m.confirmRemove = false
+
+	state, err := LoadState()
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("✗ Could not load state: %v", err)
+		return
+	}
+
+	m.statusMessage = "No ai-menu aliases found in shell startup files"
+	if err := RemoveShellIntegration(state, func(msg string) { m.statusMessage = msg }); err != nil {
+		m.statusMessage = fmt.Sprintf("✗ Could not remove shell integration: %v", err)
+		return
+	}
+
+	m.activeEnv = state.Active
+}
+
 func (m model) handleDown() int {
 	var maxLen int
 	switch m.state {
-	case cliToolsView:
-		// +1 for "Select All" option at the top
-		maxLen = len(m.cliTools) + 1
-	case vscodeExtensionsView:
-		// +1 for "Select All" option at the top
-		maxLen = len(m.vscodeExts) + 1
-	case specialToolsView:
-		// +1 for "Select All" option at the top
-		maxLen = len(m.specialTools) + 1
-	case cliEnhancersView:
-		// +1 for "Select All" option at the top
-		maxLen = len(m.cliEnhancers) + 1
+	case welcomeView:
+		maxLen = len(m.environments)
+	case profilesView:
+		maxLen = len(m.profiles)
+	case cliToolsView, vscodeExtensionsView, specialToolsView, cliEnhancersView:
+		list, _ := m.selectionList(m.state)
+		maxLen = list.maxCursor() + 1
+	case shellTargetsView:
+		maxLen = len(supportedShells)
+	case aliasNamesView:
+		maxLen = len(m.aliasList)
+	case installView:
+		maxLen = m.pageScrollLimit(m.installSummaryParts()) + 1
+	case doneView:
+		maxLen = m.pageScrollLimit(m.doneParts()) + 1
 	default:
 		return m.cursor
 	}
@@ -59,164 +422,206 @@ func (m model) handleDown() int {
 	return m.cursor
 }
 
+// isAvailable reports whether a catalog item can be installed on the host platform
+func (m model) isAvailable(item string) bool {
+	entry, ok := lookupCatalogEntry(item)
+	return !ok || entry.SupportsPlatform(m.platform)
+}
+
+// selectedShells returns the chosen shell targets in display order
+func (m model) selectedShells() []string {
+	shells := make([]string, 0, len(m.shellTargets))
+	for _, shell := range supportedShells {
+		if m.shellTargets[shell] {
+			shells = append(shells, shell)
+		}
+	}
+	return shells
+}
+
 func (m *model) toggleSelection() {
 	switch m.state {
-	case cliToolsView:
-		if m.cursor == 0 {
-			// Toggle Select All
-			if len(m.selectedCLI) == len(m.cliTools) {
-				// All selected, deselect all
-				m.selectedCLI = make(map[string]bool)
+	case aliasNamesView:
+		// Disable the alias under the cursor, or restore its default name
+		if m.cursor < len(m.aliasList) {
+			alias := m.aliasList[m.cursor]
+			if _, enabled := m.aliasNames.Lookup(alias); enabled {
+				m.aliasNames.Set(alias, "")
 			} else {
-				// Not all selected, select all
-				for _, tool := range m.cliTools {
-					m.selectedCLI[tool] = true
-				}
-			}
-		} else if m.cursor <= len(m.cliTools) {
-			tool := m.cliTools[m.cursor-1]
-			m.selectedCLI[tool] = !m.selectedCLI[tool]
-			if !m.selectedCLI[tool] {
-				delete(m.selectedCLI, tool)
+				m.aliasNames.Set(alias, alias)
 			}
+			m.refreshAliasConflicts()
 		}
-	case vscodeExtensionsView:
-		if m.cursor == 0 {
-			// Toggle Select All
-			if len(m.selectedVSCode) == len(m.vscodeExts) {
-				// All selected, deselect all
-				m.selectedVSCode = make(map[string]bool)
+	case shellTargetsView:
+		if m.cursor < len(supportedShells) {
+			shell := supportedShells[m.cursor]
+			if m.shellTargets[shell] {
+				delete(m.shellTargets, shell)
 			} else {
-				// Not all selected, select all
-				for _, ext := range m.vscodeExts {
-					m.selectedVSCode[ext] = true
-				}
-			}
-		} else if m.cursor <= len(m.vscodeExts) {
-			ext := m.vscodeExts[m.cursor-1]
-			m.selectedVSCode[ext] = !m.selectedVSCode[ext]
-			if !m.selectedVSCode[ext] {
-				delete(m.selectedVSCode, ext)
+				m.shellTargets[shell] = true
 			}
 		}
-	case specialToolsView:
-		if m.cursor == 0 {
-			// Toggle Select All
-			if len(m.selectedSpecial) == len(m.specialTools) {
-				// All selected, deselect all
-				m.selectedSpecial = make(map[string]bool)
-			} else {
-				// Not all selected, select all
-				for _, tool := range m.specialTools {
-					m.selectedSpecial[tool] = true
-				}
-			}
-		} else if m.cursor <= len(m.specialTools) {
-			tool := m.specialTools[m.cursor-1]
-			m.selectedSpecial[tool] = !m.selectedSpecial[tool]
-			if !m.selectedSpecial[tool] {
-				delete(m.selectedSpecial, tool)
-			}
+	case cliToolsView, vscodeExtensionsView, specialToolsView, cliEnhancersView:
+		list, _ := m.selectionList(m.state)
+		list.toggle(m.cursor)
+	}
+}
+
+// plannedAliases returns the default names of the aliases the installation will write:
+// those of the selected tools plus the tools already recorded in the target environment
+func (m model) plannedAliases() []string {
+	names := []string{}
+	add := func(name string) {
+		if !containsString(names, name) {
+			names = append(names, name)
 		}
-	case cliEnhancersView:
-		if m.cursor == 0 {
-			// Toggle Select All
-			if len(m.selectedCLIEnhancers) == len(m.cliEnhancers) {
-				// All selected, deselect all
-				m.selectedCLIEnhancers = make(map[string]bool)
-			} else {
-				// Not all selected, select all
-				for _, enhancer := range m.cliEnhancers {
-					m.selectedCLIEnhancers[enhancer] = true
-				}
-			}
-		} else if m.cursor <= len(m.cliEnhancers) {
-			enhancer := m.cliEnhancers[m.cursor-1]
-			m.selectedCLIEnhancers[enhancer] = !m.selectedCLIEnhancers[enhancer]
-			if !m.selectedCLIEnhancers[enhancer] {
-				delete(m.selectedCLIEnhancers, enhancer)
-			}
+	}
+
+	for _, tool := range m.cliTools {
+		if m.selectedCLI[tool] {
+			add(getAliasName(getPackageNameForCLI(tool)))
+		}
+	}
+	for _, enhancer := range m.cliEnhancers {
+		if m.selectedCLIEnhancers[enhancer] {
+			add(getAliasName(getPackageNameForCLIEnhancer(enhancer)))
+		}
+	}
+	for _, tool := range m.specialTools {
+		if !m.selectedSpecial[tool] {
+			continue
+		}
+		// Only modal and the batcat fallback for bat are exposed as aliases
+		if entry, ok := lookupCatalogEntry(tool); ok && (entry.Package == "modal" || entry.Package == "bat") {
+			add(entry.Package)
 		}
 	}
-}
 
-func (m model) performInstallation() tea.Cmd {
-	return func() tea.Msg {
-		// Create a progress callback that sends messages via the program
-		progress := func(msg string) {
-			if program != nil {
-				program.Send(installMsg{message: msg})
+	// Tools installed earlier keep their aliases when installing into the same environment
+	hasCLITools := len(m.selectedCLI) > 0
+	target := envDirFor(m.installPath, m.envName)
+	for _, env := range m.environments {
+		if env.Path != target {
+			continue
+		}
+		for _, tool := range env.Tools {
+			add(tool.Alias)
+			if tool.Category == categoryCLI {
+				hasCLITools = true
 			}
 		}
+	}
 
-		// Collect all results
-		allResults := []InstallResult{}
+	// npm and npx are routed through the environment whenever it holds CLI tools
+	if hasCLITools {
+		add("npx")
+		add("npm")
+	}
+	return names
+}
 
-		// ALWAYS ensure core dependencies first (Node 22.* and Python 3.12.*)
-		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		if !EnsureCoreDependencies(m.installPath, progress) {
-			progress("✗ Failed to ensure core dependencies")
-			return installCompleteMsg{results: allResults}
+// refreshAliasConflicts re-checks the enabled alias names against the user's shell and PATH
+func (m *model) refreshAliasConflicts() {
+	names := make([]string, 0, len(m.aliasList))
+	for _, alias := range m.aliasList {
+		if name, enabled := m.aliasNames.Lookup(alias); enabled {
+			names = append(names, name)
 		}
-		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		progress("")
+	}
+	m.aliasConflicts = findAliasConflicts(names, m.selectedShells(), envDirFor(m.installPath, m.envName))
+}
 
-		// Convert selected maps to slices
-		cliTools := make([]string, 0, len(m.selectedCLI))
-		for tool := range m.selectedCLI {
-			// Convert display name to package name for installation
-			packageName := getPackageNameForCLI(tool)
-			cliTools = append(cliTools, packageName)
+// activeAliasConflicts describes the enabled aliases that would shadow existing commands
+func (m model) activeAliasConflicts() []string {
+	conflicts := []string{}
+	for _, alias := range m.aliasList {
+		name, enabled := m.aliasNames.Lookup(alias)
+		if !enabled {
+			continue
 		}
-
-		vscodeExts := make([]string, 0, len(m.selectedVSCode))
-		for ext := range m.selectedVSCode {
-			vscodeExts = append(vscodeExts, ext)
+		if conflict, exists := m.aliasConflicts[name]; exists {
+			conflicts = append(conflicts, fmt.Sprintf("%s shadows %s", name, conflict))
 		}
+	}
+	return conflicts
+}
 
-		specialTools := make([]string, 0, len(m.selectedSpecial))
-		for tool := range m.selectedSpecial {
-			specialTools = append(specialTools, tool)
-		}
+// renameAlias applies the name typed for the alias under the cursor
+func (m *model) renameAlias() {
+	name := strings.TrimSpace(m.aliasInput.Value())
+	if !validAliasName.MatchString(name) {
+		m.aliasErr = fmt.Errorf("invalid alias name %q", name)
+		return
+	}
 
-		cliEnhancers := make([]string, 0, len(m.selectedCLIEnhancers))
-		for enhancer := range m.selectedCLIEnhancers {
-			cliEnhancers = append(cliEnhancers, enhancer)
+	alias := m.aliasList[m.cursor]
+	for _, other := range m.aliasList {
+		if other == alias {
+			continue
 		}
-
-		// Perform installations
-		if len(cliTools) > 0 {
-			progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-			results := InstallCLITools(cliTools, m.installPath, progress)
-			allResults = append(allResults, results...)
-			progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-			progress("")
+		if otherName, enabled := m.aliasNames.Lookup(other); enabled && otherName == name {
+			m.aliasErr = fmt.Errorf("%s is already used by the %s alias", name, other)
+			return
 		}
+	}
 
-		if len(vscodeExts) > 0 {
-			progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-			results := InstallVSCodeExtensions(vscodeExts, progress)
-			allResults = append(allResults, results...)
-			progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-			progress("")
-		}
+	m.aliasNames.Set(alias, name)
+	m.editingAlias = false
+	m.aliasErr = nil
+	m.aliasInput.Blur()
+	m.refreshAliasConflicts()
+}
 
-		if len(specialTools) > 0 {
-			progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-			results := InstallSpecialTools(specialTools, m.installPath, progress)
-			allResults = append(allResults, results...)
-			progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-			progress("")
+// installPlan captures the selections made in the TUI for the installation pipeline
+func (m model) installPlan() InstallPlan {
+	plan := InstallPlan{
+		InstallPath:  m.installPath,
+		EnvName:      m.envName,
+		Core:         m.config.Core,
+		Isolate:      m.isolate,
+		ShellTargets: m.selectedShells(),
+		Mode:         m.shellMode,
+		AliasNames:   m.aliasNames,
+		Credentials:  m.enteredCredentials(),
+		Registries:   m.config.Registries,
+		Concurrency:  m.config.UI.Concurrency,
+	}
+
+	// Keep catalog order so the installation runs in the order tools are listed
+	for _, tool := range m.cliTools {
+		if m.selectedCLI[tool] {
+			plan.CLITools = append(plan.CLITools, tool)
+		}
+	}
+	for _, ext := range m.vscodeExts {
+		if m.selectedVSCode[ext] {
+			plan.VSCodeExtensions = append(plan.VSCodeExtensions, ext)
+		}
+	}
+	for _, tool := range m.specialTools {
+		if m.selectedSpecial[tool] {
+			plan.SpecialTools = append(plan.SpecialTools, tool)
 		}
+	}
+	for _, enhancer := range m.cliEnhancers {
+		if m.selectedCLIEnhancers[enhancer] {
+			plan.CLIEnhancers = append(plan.CLIEnhancers, enhancer)
+		}
+	}
+	return plan
+}
 
-		if len(cliEnhancers) > 0 {
-			progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-			results := InstallCLIEnhancers(cliEnhancers, m.installPath, progress)
-			allResults = append(allResults, results...)
-			progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-			progress("")
+func (m model) performInstallation() tea.Cmd {
+	return func() tea.Msg {
+		// Create a progress callback that sends messages via the program
+		progress := func(msg string) {
+			if program != nil {
+				program.Send(installMsg{message: msg})
+			}
 		}
 
-		return installCompleteMsg{results: allResults}
+		// Failures are reported through progress and the results shown in the done view
+		results, _ := RunInstallation(m.installPlan(), progress)
+		return installCompleteMsg{results: results}
 	}
 }
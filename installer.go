@@ -6,24 +6,41 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
 )
 
 type InstallResult struct {
-	Name    string
-	Success bool
-	Error   error
-	Message string
+	Name        string
+	Success     bool
+	Error       error
+	Message     string
+	Environment string        // pixi environment the tool was installed into, empty for the default
+	Category    string        // catalog category, e.g. cli
+	Backend     string        // how the tool was installed, e.g. npm or apt
+	Version     string        // installed version, when it could be detected
+	Duration    time.Duration // time spent installing the tool
+	LogPath     string        // file holding the installer's output
 }
 
 type ProgressCallback func(message string)
 
-// EnsureCoreDependencies ensures Node 22.*, Python 3.12.*, and uv are in the pixi environment
-// It only adds them if they don't already exist, preventing reinstalls
-func EnsureCoreDependencies(installPath string, progress ProgressCallback) bool {
-	progress("Ensuring core dependencies (Node 22.*, Python 3.12.*, and uv) are available...")
+// EnsureCoreDependencies ensures the configured Node, Python, uv and extra base packages are in the pixi environment
+// It only adds them if they don't already exist with the requested version, preventing reinstalls
+func EnsureCoreDependencies(envDir string, core CoreConfig, progress ProgressCallback) bool {
+	deps := core.Dependencies()
+	labels := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		labels = append(labels, dep.Label())
+	}
+	progress(fmt.Sprintf("Ensuring core dependencies (%s) are available...", strings.Join(labels, ", ")))
 
-	// Append ai-dev-pixi to the provided parent path
-	envDir := installPath + "/ai-dev-pixi"
+	// Refuse to build an environment that cannot be used on this machine
+	if err := validatePlatforms(core.Platforms, hostPlatform()); err != nil {
+		progress(fmt.Sprintf("✗ Invalid platform configuration: %v", err))
+		return false
+	}
 
 	// Create directory if it doesn't exist
 	if err := os.MkdirAll(envDir, 0755); err != nil {
@@ -43,7 +60,7 @@ func EnsureCoreDependencies(installPath string, progress ProgressCallback) bool
 
 	// Initialize pixi project if it doesn't exist
 	progress("Initializing pixi project...")
-	cmd := exec.Command("pixi", "init", "--platform", "linux-64", "--platform", "linux-aarch64")
+	cmd := exec.Command("pixi", pixiInitArgs(core.Platforms)...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -51,70 +68,110 @@ func EnsureCoreDependencies(installPath string, progress ProgressCallback) bool
 		progress(fmt.Sprintf("⚠️  Pixi init failed, project may already exist: %v", err))
 	}
 
-	// Check if nodejs already exists in the pixi.toml
-	pixiContent, err := os.ReadFile("pixi.toml")
-	hasNodejs := err == nil && bytes.Contains(pixiContent, []byte("nodejs"))
-	hasPython := err == nil && bytes.Contains(pixiContent, []byte("python"))
-	hasUv := err == nil && bytes.Contains(pixiContent, []byte("uv"))
-
-	// Add nodejs dependency if not already present
-	if !hasNodejs {
-		progress("Adding nodejs 22.* to pixi environment...")
-		cmd = exec.Command("pixi", "add", "nodejs=22.*")
+	// Add configured platforms missing from an existing project
+	declared := readPixiPlatforms("pixi.toml")
+	for _, platform := range core.Platforms {
+		if declared[platform] {
+			continue
+		}
+		progress(fmt.Sprintf("Adding platform %s to pixi project...", platform))
+		cmd = exec.Command("pixi", "workspace", "platform", "add", platform)
 		stdout.Reset()
 		stderr.Reset()
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
 		if err := cmd.Run(); err != nil {
-			msg := fmt.Sprintf("✗ Failed to add nodejs: %v", err)
-			progress(msg)
+			progress(fmt.Sprintf("✗ Failed to add platform %s: %v", platform, err))
 			return false
 		}
-		progress("✓ nodejs 22.* added to pixi environment")
-	} else {
-		progress("✓ nodejs 22.* already in pixi environment, skipping")
 	}
 
-	// Add python dependency if not already present
-	if !hasPython {
-		progress("Adding python 3.12.* to pixi environment...")
-		cmd = exec.Command("pixi", "add", "python=3.12.*")
-		stdout.Reset()
-		stderr.Reset()
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
-		if err := cmd.Run(); err != nil {
-			msg := fmt.Sprintf("✗ Failed to add python: %v", err)
-			progress(msg)
-			return false
+	// Read the dependencies already declared in pixi.toml
+	existing := readPixiDependencies("pixi.toml")
+
+	for _, dep := range deps {
+		// Skip packages that are already present with the requested version
+		if current, ok := existing[dep.Name]; ok && (dep.Version == "" || current == dep.Version) {
+			progress(fmt.Sprintf("✓ %s already in pixi environment, skipping", dep.Label()))
+			continue
 		}
-		progress("✓ python 3.12.* added to pixi environment")
-	} else {
-		progress("✓ python 3.12.* already in pixi environment, skipping")
-	}
 
-	// Add uv dependency if not already present
-	if !hasUv {
-		progress("Adding uv to pixi environment...")
-		cmd = exec.Command("pixi", "add", "uv")
+		progress(fmt.Sprintf("Adding %s to pixi environment...", dep.Label()))
+		cmd = exec.Command("pixi", "add", dep.Spec())
 		stdout.Reset()
 		stderr.Reset()
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
 		if err := cmd.Run(); err != nil {
-			msg := fmt.Sprintf("✗ Failed to add uv: %v", err)
+			msg := fmt.Sprintf("✗ Failed to add %s: %v", dep.Name, err)
 			progress(msg)
 			return false
 		}
-		progress("✓ uv added to pixi environment")
-	} else {
-		progress("✓ uv already in pixi environment, skipping")
+		progress(fmt.Sprintf("✓ %s added to pixi environment", dep.Label()))
 	}
 
 	progress("✓ Core dependencies are ready")
 	return true
 }
 
+// pixiInitArgs returns the pixi arguments that create a project for the platforms
+func pixiInitArgs(platforms []string) []string {
+	args := []string{"init"}
+	for _, platform := range platforms {
+		args = append(args, "--platform", platform)
+	}
+	return args
+}
+
+// readPixiDependencies returns the version specs from the [dependencies] table of a pixi manifest
+func readPixiDependencies(manifestPath string) map[string]string {
+	deps := make(map[string]string)
+
+	var manifest struct {
+		Dependencies map[string]interface{} `toml:"dependencies"`
+	}
+	if _, err := toml.DecodeFile(manifestPath, &manifest); err != nil {
+		return deps
+	}
+
+	for name, value := range manifest.Dependencies {
+		switch v := value.(type) {
+		case string:
+			deps[name] = v
+		case map[string]interface{}:
+			// Table form: nodejs = { version = "22.*" }
+			if version, ok := v["version"].(string); ok {
+				deps[name] = version
+			} else {
+				deps[name] = ""
+			}
+		}
+	}
+	return deps
+}
+
+// readPixiPlatforms returns the platforms declared in the [workspace] (or legacy [project]) table
+func readPixiPlatforms(manifestPath string) map[string]bool {
+	platforms := make(map[string]bool)
+
+	var manifest struct {
+		Workspace struct {
+			Platforms []string `toml:"platforms"`
+		} `toml:"workspace"`
+		Project struct {
+			Platforms []string `toml:"platforms"`
+		} `toml:"project"`
+	}
+	if _, err := toml.DecodeFile(manifestPath, &manifest); err != nil {
+		return platforms
+	}
+
+	for _, p := range append(manifest.Workspace.Platforms, manifest.Project.Platforms...) {
+		platforms[p] = true
+	}
+	return platforms
+}
+
 // getAliasName returns the desired shell alias name for a given package/tool
 func getAliasName(packageName string) string {
 	// Map specific package names to their desired aliases
@@ -179,8 +236,23 @@ func getCommandName(packageName string) string {
 	return packageName
 }
 
+// commandError adds the last line an installer printed on stderr to its exit error, which is
+// usually the reason it failed
+func commandError(err error, stderr []byte) error {
+	if err == nil {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSpace(string(stderr)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return fmt.Errorf("%w: %s", err, last)
+	}
+	return err
+}
+
 // InstallCLITools installs the selected CLI tools in a new pixi environment
-func InstallCLITools(tools []string, installPath string, progress ProgressCallback) []InstallResult {
+// pixiEnvFor names the pixi feature/environment of each tool, empty for the default environment;
+// upgrade makes the installers upgrade tools that are already installed
+func InstallCLITools(tools []string, envDir string, pixiEnvFor func(string) string, upgrade bool, progress ProgressCallback) []InstallResult {
 	results := make([]InstallResult, 0, len(tools))
 
 	if len(tools) == 0 {
@@ -197,9 +269,6 @@ func InstallCLITools(tools []string, installPath string, progress ProgressCallba
 
 	progress("Installing CLI tools...")
 
-	// Append ai-dev-pixi to the provided parent path
-	envDir := installPath + "/ai-dev-pixi"
-
 	// Change to the environment directory
 	if err := os.Chdir(envDir); err != nil {
 		msg := fmt.Sprintf("✗ Failed to change to directory %s: %v", envDir, err)
@@ -213,61 +282,23 @@ func InstallCLITools(tools []string, installPath string, progress ProgressCallba
 	var stdout, stderr bytes.Buffer
 	for _, toolName := range toolNames {
 		progress(fmt.Sprintf("Installing %s...", toolName))
+		start := time.Now()
+		stdout.Reset()
+		stderr.Reset()
 
-		var cmd *exec.Cmd
+		// Give npm and uv tools their own pixi environment in isolation mode
+		pixiEnv := pixiEnvFor(toolName)
+		backend := getInstallBackend(toolName)
 		var err error
+		if pixiEnv != "" {
+			err = ensureToolEnvironment(envDir, pixiEnv, backend, progress)
+		}
 
-		// Handle special CLI tools installed via curl scripts or custom installers
-		if toolName == "droid" {
-			cmd = exec.Command("bash", "-c", "curl -fsSL https://app.factory.ai/cli | sh")
-			stdout.Reset()
-			stderr.Reset()
-			cmd.Stdout = &stdout
-			cmd.Stderr = &stderr
-			err = cmd.Run()
-		} else if toolName == "goose" {
-			cmd = exec.Command("bash", "-c", "curl -fsSL https://github.com/block/goose/releases/download/stable/download_cli.sh | CONFIGURE=false bash")
-			stdout.Reset()
-			stderr.Reset()
-			cmd.Stdout = &stdout
-			cmd.Stderr = &stderr
-			err = cmd.Run()
-		} else if toolName == "kiro" {
-			cmd = exec.Command("bash", "-c", "curl -fsSL https://cli.kiro.dev/install | bash")
-			stdout.Reset()
-			stderr.Reset()
-			cmd.Stdout = &stdout
-			cmd.Stderr = &stderr
-			err = cmd.Run()
-		} else if toolName == "kimi-cli" {
-			cmd = exec.Command("pixi", "run", "uv", "tool", "install", "--python", "3.13", "kimi-cli")
-			stdout.Reset()
-			stderr.Reset()
-			cmd.Stdout = &stdout
-			cmd.Stderr = &stderr
-			err = cmd.Run()
-		} else if toolName == "openhands" {
-			cmd = exec.Command("pixi", "run", "uv", "tool", "install", "openhands")
-			stdout.Reset()
-			stderr.Reset()
-			cmd.Stdout = &stdout
-			cmd.Stderr = &stderr
-			err = cmd.Run()
-		} else if toolName == "plandex" {
-			cmd = exec.Command("bash", "-c", "curl -sL https://plandex.ai/install.sh | bash")
-			stdout.Reset()
-			stderr.Reset()
-			cmd.Stdout = &stdout
-			cmd.Stderr = &stderr
-			err = cmd.Run()
-		} else {
-			// Install npm packages via pixi
-			cmd = exec.Command("pixi", "run", "npm", "install", "-g", toolName)
-			stdout.Reset()
-			stderr.Reset()
+		if err == nil {
+			cmd := cliInstallCommand(toolName, envDir, pixiEnv, upgrade)
 			cmd.Stdout = &stdout
 			cmd.Stderr = &stderr
-			err = cmd.Run()
+			err = commandError(cmd.Run(), stderr.Bytes())
 		}
 
 		var msg string
@@ -279,97 +310,77 @@ func InstallCLITools(tools []string, installPath string, progress ProgressCallba
 		progress(msg)
 
 		results = append(results, InstallResult{
-			Name:    toolName,
-			Success: err == nil,
-			Error:   err,
-			Message: msg,
+			Name:        toolName,
+			Success:     err == nil,
+			Error:       err,
+			Message:     msg,
+			Environment: pixiEnv,
+			Category:    categoryCLI,
+			Backend:     backend,
+			Duration:    time.Since(start),
+			LogPath:     saveInstallLog(toolName, stdout.Bytes(), stderr.Bytes()),
 		})
 	}
 
 	progress(fmt.Sprintf("📦 CLI tools installed in pixi environment at: %s", envDir))
+	progress(fmt.Sprintf("To use the tools, run: cd %s && pixi shell", envDir))
 
-	// Add aliases to ~/.zshrc for easy access
-	homeDir, err := os.UserHomeDir()
-	if err == nil {
-		zshrcPath := homeDir + "/.zshrc"
-		progress("Adding aliases to ~/.zshrc...")
-
-		// Read existing .zshrc content
-		existingContent, err := os.ReadFile(zshrcPath)
-		if err != nil && !os.IsNotExist(err) {
-			progress(fmt.Sprintf("⚠️  Could not read ~/.zshrc: %v", err))
-		}
-
-		// Open .zshrc for appending
-		f, err := os.OpenFile(zshrcPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			progress(fmt.Sprintf("⚠️  Could not open ~/.zshrc for writing: %v", err))
-		} else {
-			defer f.Close()
-
-			// Add a comment header if this is the first time
-			markerComment := "# AI Menu CLI Tool Aliases"
-			if !bytes.Contains(existingContent, []byte(markerComment)) {
-				f.WriteString("\n" + markerComment + "\n")
-			}
+	return results
+}
 
-			// Add alias for each successfully installed tool
-			aliasesAdded := 0
-			for _, result := range results {
-				if result.Success {
-					// Map npm package names to desired alias/command names
-					aliasName := getAliasName(result.Name)
-					commandName := getCommandName(result.Name)
-
-					// Create alias with the correct command name
-					aliasLine := fmt.Sprintf("alias %s='pixi run --manifest-path %s %s'\n",
-						aliasName, envDir, commandName)
-
-					// Check if alias already exists
-					if !bytes.Contains(existingContent, []byte(aliasLine)) {
-						if _, err := f.WriteString(aliasLine); err == nil {
-							aliasesAdded++
-						}
-					}
-				}
-			}
+// cliInstallCommand returns the command that installs a CLI tool, optionally into a dedicated pixi environment.
+// With upgrade, uv tools that are already installed are upgraded; npm install -g always fetches the latest.
+func cliInstallCommand(toolName, envDir, pixiEnv string, upgrade bool) *exec.Cmd {
+	var cmd *exec.Cmd
+
+	// Handle special CLI tools installed via curl scripts or custom installers
+	switch toolName {
+	case "droid":
+		cmd = exec.Command("bash", "-c", "curl -fsSL https://app.factory.ai/cli | sh")
+	case "goose":
+		cmd = exec.Command("bash", "-c", "curl -fsSL https://github.com/block/goose/releases/download/stable/download_cli.sh | CONFIGURE=false bash")
+	case "kiro":
+		cmd = exec.Command("bash", "-c", "curl -fsSL https://cli.kiro.dev/install | bash")
+	case "plandex":
+		cmd = exec.Command("bash", "-c", "curl -sL https://plandex.ai/install.sh | bash")
+	case "kimi-cli":
+		cmd = exec.Command("pixi", pixiRunArgs(pixiEnv, uvToolInstallArgs(upgrade, "--python", "3.13", "kimi-cli")...)...)
+	case "openhands":
+		cmd = exec.Command("pixi", pixiRunArgs(pixiEnv, uvToolInstallArgs(upgrade, "openhands")...)...)
+	default:
+		// Install npm packages via pixi
+		cmd = exec.Command("pixi", pixiRunArgs(pixiEnv, "npm", "install", "-g", toolName)...)
+	}
+
+	if pixiEnv != "" && getInstallBackend(toolName) == backendUv {
+		cmd.Env = isolatedUvEnv(envDir, pixiEnv)
+	}
+	return cmd
+}
 
-			if aliasesAdded > 0 {
-				progress(fmt.Sprintf("✓ Added %d alias(es) to ~/.zshrc", aliasesAdded))
-				progress("Run 'source ~/.zshrc' or restart your shell to use the aliases")
-			} else {
-				progress("Aliases already exist in ~/.zshrc")
-			}
+// uvToolInstallArgs returns a uv tool install command line; with upgrade, a tool that is
+// already installed is upgraded instead of left as it is
+func uvToolInstallArgs(upgrade bool, args ...string) []string {
+	install := []string{"uv", "tool", "install"}
+	if upgrade {
+		install = append(install, "--upgrade")
+	}
+	return append(install, args...)
+}
 
-			// Add npx and npm aliases if any CLI tools were successfully installed
-			hasSuccess := false
-			for _, result := range results {
-				if result.Success {
-					hasSuccess = true
-					break
-				}
-			}
-			if hasSuccess {
-				npxAliasLine := fmt.Sprintf("alias npx='pixi run --manifest-path %s npx'\n", envDir)
-				if !bytes.Contains(existingContent, []byte(npxAliasLine)) {
-					if _, err := f.WriteString(npxAliasLine); err == nil {
-						progress("✓ Added npx alias to ~/.zshrc")
-					}
-				}
-				npmAliasLine := fmt.Sprintf("alias npm='pixi run --manifest-path %s npm'\n", envDir)
-				if !bytes.Contains(existingContent, []byte(npmAliasLine)) {
-					if _, err := f.WriteString(npmAliasLine); err == nil {
-						progress("✓ Added npm alias to ~/.zshrc")
-					}
-				}
-			}
+// enhancerInstallCommand returns the command that installs a CLI enhancer, optionally into a dedicated pixi environment
+func enhancerInstallCommand(packageName, envDir, pixiEnv string, upgrade bool) *exec.Cmd {
+	// Handle special CLI enhancers installed via uv tool install
+	if packageName == "specify-cli" {
+		cmd := exec.Command("pixi", pixiRunArgs(pixiEnv, uvToolInstallArgs(upgrade, "--from", "git+https://github.com/github/spec-kit.git", packageName)...)...)
+		if pixiEnv != "" {
+			cmd.Env = isolatedUvEnv(envDir, pixiEnv)
 		}
+		return cmd
 	}
 
-	progress(fmt.Sprintf("To use the tools, run: cd %s && pixi shell", envDir))
-	progress("Or use the aliases added to ~/.zshrc (restart shell or run: source ~/.zshrc)")
-
-	return results
+	// Install npm packages via pixi
+	return exec.Command("pixi", pixiRunArgs(pixiEnv, "npm", "install", "-g", packageName)...)
 }
 
 // InstallVSCodeExtensions installs the selected VS Code extensions
@@ -390,13 +401,14 @@ func InstallVSCodeExtensions(extensions []string, progress ProgressCallback) []I
 		extID := strings.TrimSpace(parts[0])
 
 		progress(fmt.Sprintf("Installing %s...", extID))
+		start := time.Now()
 
 		cmd := exec.Command("code", "--install-extension", extID)
 		var stdout, stderr bytes.Buffer
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
 
-		err := cmd.Run()
+		err := commandError(cmd.Run(), stderr.Bytes())
 		var msg string
 		if err == nil {
 			msg = fmt.Sprintf("✓ %s installed successfully", extID)
@@ -406,23 +418,65 @@ func InstallVSCodeExtensions(extensions []string, progress ProgressCallback) []I
 		progress(msg)
 
 		results = append(results, InstallResult{
-			Name:    extID,
-			Success: err == nil,
-			Error:   err,
-			Message: msg,
+			Name:     extID,
+			Success:  err == nil,
+			Error:    err,
+			Message:  msg,
+			Category: categoryVSCode,
+			Backend:  backendVSCode,
+			Duration: time.Since(start),
+			LogPath:  saveInstallLog(extID, stdout.Bytes(), stderr.Bytes()),
 		})
 	}
 
 	return results
 }
 
+// aptPackage returns the Ubuntu package a special tool is installed from
+func aptPackage(toolName string) (string, bool) {
+	switch toolName {
+	case "ripgrep", "jq", "yq", "bat":
+		return toolName, true
+	case "fd":
+		// fd is packaged as fd-find in Ubuntu
+		return "fd-find", true
+	case "exa":
+		// exa has been replaced by eza in Ubuntu 24.04
+		return "eza", true
+	}
+	return "", false
+}
+
+// specialInstallCommand returns the command that installs a special tool, or nil for an
+// unknown tool. modal must be installed from the pixi environment's directory; with upgrade
+// it is upgraded when already installed.
+func specialInstallCommand(toolName string, upgrade bool) *exec.Cmd {
+	if pkg, ok := aptPackage(toolName); ok {
+		return exec.Command("sudo", "apt-get", "install", "-y", pkg)
+	}
+
+	switch toolName {
+	case "helm":
+		return exec.Command("bash", "-c", "curl https://raw.githubusercontent.com/helm/helm/main/scripts/get-helm-3 | bash")
+	case "gh":
+		// Install GitHub CLI using official install script
+		return exec.Command("bash", "-c", "curl -fsSL https://cli.github.com/packages/githubcli-archive-keyring.gpg | sudo dd of=/usr/share/keyrings/githubcli-archive-keyring.gpg && echo \"deb [arch=$(dpkg --print-architecture) signed-by=/usr/share/keyrings/githubcli-archive-keyring.gpg] https://cli.github.com/packages stable main\" | sudo tee /etc/apt/sources.list.d/github-cli.list > /dev/null && sudo apt update && sudo apt install -y gh")
+	case "lazygit":
+		return exec.Command("bash", "-c", "LAZYGIT_VERSION=$(curl -s \"https://api.github.com/repos/jesseduffield/lazygit/releases/latest\" | grep -Po '\"tag_name\": \"v\\K[^\"]*') && curl -Lo lazygit.tar.gz \"https://github.com/jesseduffield/lazygit/releases/latest/download/lazygit_${LAZYGIT_VERSION}_Linux_x86_64.tar.gz\" && tar xf lazygit.tar.gz lazygit && sudo install lazygit /usr/local/bin && rm lazygit lazygit.tar.gz")
+	case "modal":
+		// Install modal via uv pip in the pixi environment
+		if upgrade {
+			return exec.Command("pixi", "run", "uv", "pip", "install", "-U", "modal")
+		}
+		return exec.Command("pixi", "run", "uv", "pip", "install", "modal")
+	}
+	return nil
+}
+
 // InstallSpecialTools installs the selected special tools
-func InstallSpecialTools(tools []string, installPath string, progress ProgressCallback) []InstallResult {
+func InstallSpecialTools(tools []string, envDir string, upgrade bool, progress ProgressCallback) []InstallResult {
 	results := make([]InstallResult, 0, len(tools))
 
-	// Append ai-dev-pixi to the provided parent path for pixi environment
-	envDir := installPath + "/ai-dev-pixi"
-
 	// Store current directory to restore later
 	originalDir, err := os.Getwd()
 	if err != nil {
@@ -435,37 +489,18 @@ func InstallSpecialTools(tools []string, installPath string, progress ProgressCa
 		toolName := strings.TrimSpace(parts[0])
 
 		progress(fmt.Sprintf("Installing %s...", toolName))
+		start := time.Now()
 
-		var cmd *exec.Cmd
-		switch toolName {
-		case "helm":
-			cmd = exec.Command("bash", "-c", "curl https://raw.githubusercontent.com/helm/helm/main/scripts/get-helm-3 | bash")
-		case "gh":
-			// Install GitHub CLI using official install script
-			cmd = exec.Command("bash", "-c", "curl -fsSL https://cli.github.com/packages/githubcli-archive-keyring.gpg | sudo dd of=/usr/share/keyrings/githubcli-archive-keyring.gpg && echo \"deb [arch=$(dpkg --print-architecture) signed-by=/usr/share/keyrings/githubcli-archive-keyring.gpg] https://cli.github.com/packages stable main\" | sudo tee /etc/apt/sources.list.d/github-cli.list > /dev/null && sudo apt update && sudo apt install -y gh")
-		case "ripgrep":
-			// Install ripgrep via apt-get
-			cmd = exec.Command("sudo", "apt-get", "install", "-y", "ripgrep")
-		case "jq", "yq", "bat":
-			// Install via apt-get
-			cmd = exec.Command("sudo", "apt-get", "install", "-y", toolName)
-		case "fd":
-			// fd is packaged as fd-find in Ubuntu
-			cmd = exec.Command("sudo", "apt-get", "install", "-y", "fd-find")
-		case "exa":
-			// exa has been replaced by eza in Ubuntu 24.04
-			cmd = exec.Command("sudo", "apt-get", "install", "-y", "eza")
-		case "lazygit":
-			cmd = exec.Command("bash", "-c", "LAZYGIT_VERSION=$(curl -s \"https://api.github.com/repos/jesseduffield/lazygit/releases/latest\" | grep -Po '\"tag_name\": \"v\\K[^\"]*') && curl -Lo lazygit.tar.gz \"https://github.com/jesseduffield/lazygit/releases/latest/download/lazygit_${LAZYGIT_VERSION}_Linux_x86_64.tar.gz\" && tar xf lazygit.tar.gz lazygit && sudo install lazygit /usr/local/bin && rm lazygit lazygit.tar.gz")
-		case "modal":
-			// Install modal via uv pip in the pixi environment
-			// First change to the pixi environment directory
+		if toolName == "modal" {
+			// modal is installed with uv pip into the pixi environment, so run pixi from its directory
 			if err := os.Chdir(envDir); err != nil {
 				progress(fmt.Sprintf("✗ Failed to change to directory %s: %v", envDir, err))
 				continue
 			}
-			cmd = exec.Command("pixi", "run", "uv", "pip", "install", "modal")
-		default:
+		}
+
+		cmd := specialInstallCommand(toolName, upgrade)
+		if cmd == nil {
 			progress(fmt.Sprintf("⚠️  Unknown tool: %s", toolName))
 			continue
 		}
@@ -474,7 +509,7 @@ func InstallSpecialTools(tools []string, installPath string, progress ProgressCa
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
 
-		err := cmd.Run()
+		err := commandError(cmd.Run(), stderr.Bytes())
 		var msg string
 		if err == nil {
 			msg = fmt.Sprintf("✓ %s installed successfully", toolName)
@@ -484,23 +519,17 @@ func InstallSpecialTools(tools []string, installPath string, progress ProgressCa
 		progress(msg)
 
 		result := InstallResult{
-			Name:    toolName,
-			Success: err == nil,
-			Error:   err,
-			Message: msg,
+			Name:     toolName,
+			Success:  err == nil,
+			Error:    err,
+			Message:  msg,
+			Category: categorySpecial,
+			Backend:  specialToolBackend(toolName),
+			Duration: time.Since(start),
+			LogPath:  saveInstallLog(toolName, stdout.Bytes(), stderr.Bytes()),
 		}
 
 		results = append(results, result)
-
-		// Add alias for bat to .zshrc if installation was successful
-		if toolName == "bat" && result.Success {
-			addBatAlias(progress)
-		}
-
-		// Add alias for modal to .zshrc if installation was successful
-		if toolName == "modal" && result.Success {
-			addModalAlias(envDir, progress)
-		}
 	}
 
 	// Restore original directory
@@ -511,104 +540,9 @@ func InstallSpecialTools(tools []string, installPath string, progress ProgressCa
 	return results
 }
 
-// addBatAlias adds an alias for bat pointing to batcat in .zshrc
-func addBatAlias(progress ProgressCallback) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		progress(fmt.Sprintf("⚠️  Could not get home directory: %v", err))
-		return
-	}
-
-	zshrcPath := homeDir + "/.zshrc"
-
-	// Read existing .zshrc content
-	existingContent, err := os.ReadFile(zshrcPath)
-	if err != nil && !os.IsNotExist(err) {
-		progress(fmt.Sprintf("⚠️  Could not read ~/.zshrc: %v", err))
-		return
-	}
-
-	// Check if alias already exists
-	batAliasLine := "alias bat='batcat'\n"
-	if bytes.Contains(existingContent, []byte(batAliasLine)) {
-		progress("✓ bat alias already exists in ~/.zshrc")
-		return
-	}
-
-	// Open .zshrc for appending
-	f, err := os.OpenFile(zshrcPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		progress(fmt.Sprintf("⚠️  Could not open ~/.zshrc for writing: %v", err))
-		return
-	}
-	defer f.Close()
-
-	// Add a comment header if this is the first time
-	specialToolsMarker := "# AI Menu Special Tools Aliases"
-	if !bytes.Contains(existingContent, []byte(specialToolsMarker)) {
-		f.WriteString("\n" + specialToolsMarker + "\n")
-	}
-
-	// Add the bat alias
-	if _, err := f.WriteString(batAliasLine); err != nil {
-		progress(fmt.Sprintf("⚠️  Could not write to ~/.zshrc: %v", err))
-		return
-	}
-
-	progress("✓ Added bat alias to ~/.zshrc")
-	progress("Run 'source ~/.zshrc' or restart your shell to use the alias")
-}
-
-// addModalAlias adds an alias for modal pointing to the modal pip package in .zshrc
-func addModalAlias(envDir string, progress ProgressCallback) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		progress(fmt.Sprintf("⚠️  Could not get home directory: %v", err))
-		return
-	}
-
-	zshrcPath := homeDir + "/.zshrc"
-
-	// Read existing .zshrc content
-	existingContent, err := os.ReadFile(zshrcPath)
-	if err != nil && !os.IsNotExist(err) {
-		progress(fmt.Sprintf("⚠️  Could not read ~/.zshrc: %v", err))
-		return
-	}
-
-	// Create alias using pixi environment
-	modalAliasLine := fmt.Sprintf("alias modal='pixi run --manifest-path %s/pixi.toml python -m modal'\n", envDir)
-	if bytes.Contains(existingContent, []byte(modalAliasLine)) {
-		progress("✓ modal alias already exists in ~/.zshrc")
-		return
-	}
-
-	// Open .zshrc for appending
-	f, err := os.OpenFile(zshrcPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		progress(fmt.Sprintf("⚠️  Could not open ~/.zshrc for writing: %v", err))
-		return
-	}
-	defer f.Close()
-
-	// Add a comment header if this is the first time
-	specialToolsMarker := "# AI Menu Special Tools Aliases"
-	if !bytes.Contains(existingContent, []byte(specialToolsMarker)) {
-		f.WriteString("\n" + specialToolsMarker + "\n")
-	}
-
-	// Add the modal alias
-	if _, err := f.WriteString(modalAliasLine); err != nil {
-		progress(fmt.Sprintf("⚠️  Could not write to ~/.zshrc: %v", err))
-		return
-	}
-
-	progress("✓ Added modal alias to ~/.zshrc")
-	progress("Run 'source ~/.zshrc' or restart your shell to use the alias")
-}
-
 // InstallCLIEnhancers installs the selected CLI tool enhancers in the pixi environment
-func InstallCLIEnhancers(enhancers []string, installPath string, progress ProgressCallback) []InstallResult {
+// pixiEnvFor can give each enhancer its own pixi feature/environment so enhancers no longer clobber each other
+func InstallCLIEnhancers(enhancers []string, envDir string, pixiEnvFor func(string) string, upgrade bool, progress ProgressCallback) []InstallResult {
 	results := make([]InstallResult, 0, len(enhancers))
 
 	if len(enhancers) == 0 {
@@ -617,9 +551,6 @@ func InstallCLIEnhancers(enhancers []string, installPath string, progress Progre
 
 	progress("Installing CLI tool enhancers...")
 
-	// Append ai-dev-pixi to the provided parent path
-	envDir := installPath + "/ai-dev-pixi"
-
 	// Change to the environment directory
 	if err := os.Chdir(envDir); err != nil {
 		msg := fmt.Sprintf("✗ Failed to change to directory %s: %v", envDir, err)
@@ -635,24 +566,24 @@ func InstallCLIEnhancers(enhancers []string, installPath string, progress Progre
 		// Convert display name to package name
 		packageName := getPackageNameForCLIEnhancer(enhancer)
 		progress(fmt.Sprintf("Installing %s...", enhancer))
+		start := time.Now()
+		stdout.Reset()
+		stderr.Reset()
 
-		var cmd *exec.Cmd
+		// Give each enhancer its own pixi environment in isolation mode
+		pixiEnv := pixiEnvFor(packageName)
 		var err error
-
-		// Handle special CLI enhancers installed via uv tool install
-		if packageName == "specify-cli" {
-			cmd = exec.Command("pixi", "run", "uv", "tool", "install", "--from", "git+https://github.com/github/spec-kit.git", packageName)
-		} else {
-			// Install npm packages via pixi
-			cmd = exec.Command("pixi", "run", "npm", "install", "-g", packageName)
+		if pixiEnv != "" {
+			err = ensureToolEnvironment(envDir, pixiEnv, getInstallBackend(packageName), progress)
 		}
 
-		stdout.Reset()
-		stderr.Reset()
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
+		if err == nil {
+			cmd := enhancerInstallCommand(packageName, envDir, pixiEnv, upgrade)
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
 
-		err = cmd.Run()
+			err = commandError(cmd.Run(), stderr.Bytes())
+		}
 
 		var msg string
 		if err == nil {
@@ -663,72 +594,20 @@ func InstallCLIEnhancers(enhancers []string, installPath string, progress Progre
 		progress(msg)
 
 		results = append(results, InstallResult{
-			Name:    packageName,
-			Success: err == nil,
-			Error:   err,
-			Message: msg,
+			Name:        packageName,
+			Success:     err == nil,
+			Error:       err,
+			Message:     msg,
+			Environment: pixiEnv,
+			Category:    categoryEnhancer,
+			Backend:     getInstallBackend(packageName),
+			Duration:    time.Since(start),
+			LogPath:     saveInstallLog(packageName, stdout.Bytes(), stderr.Bytes()),
 		})
 	}
 
 	progress(fmt.Sprintf("📦 CLI enhancers installed in pixi environment at: %s", envDir))
-
-	// Add aliases to ~/.zshrc for easy access
-	homeDir, err := os.UserHomeDir()
-	if err == nil {
-		zshrcPath := homeDir + "/.zshrc"
-		progress("Adding aliases to ~/.zshrc...")
-
-		// Read existing .zshrc content
-		existingContent, err := os.ReadFile(zshrcPath)
-		if err != nil && !os.IsNotExist(err) {
-			progress(fmt.Sprintf("⚠️  Could not read ~/.zshrc: %v", err))
-		}
-
-		// Open .zshrc for appending
-		f, err := os.OpenFile(zshrcPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			progress(fmt.Sprintf("⚠️  Could not open ~/.zshrc for writing: %v", err))
-		} else {
-			defer f.Close()
-
-			// Add a comment header if this is the first time
-			markerComment := "# AI Menu CLI Tool Aliases"
-			if !bytes.Contains(existingContent, []byte(markerComment)) {
-				f.WriteString("\n" + markerComment + "\n")
-			}
-
-			// Add alias for each successfully installed enhancer
-			aliasesAdded := 0
-			for _, result := range results {
-				if result.Success {
-					// Map npm package names to desired alias/command names
-					aliasName := getAliasName(result.Name)
-					commandName := getCommandName(result.Name)
-
-					// Create alias with the correct command name
-					aliasLine := fmt.Sprintf("alias %s='pixi run --manifest-path %s %s'\n",
-						aliasName, envDir, commandName)
-
-					// Check if alias already exists
-					if !bytes.Contains(existingContent, []byte(aliasLine)) {
-						if _, err := f.WriteString(aliasLine); err == nil {
-							aliasesAdded++
-						}
-					}
-				}
-			}
-
-			if aliasesAdded > 0 {
-				progress(fmt.Sprintf("✓ Added %d alias(es) to ~/.zshrc", aliasesAdded))
-				progress("Run 'source ~/.zshrc' or restart your shell to use the aliases")
-			} else {
-				progress("Aliases already exist in ~/.zshrc")
-			}
-		}
-	}
-
 	progress(fmt.Sprintf("To use the enhancers, run: cd %s && pixi shell", envDir))
-	progress("Or use the aliases added to ~/.zshrc (restart shell or run: source ~/.zshrc)")
 
 	return results
 }
\ No newline at end of file
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Install backends used by CLI tools and enhancers, plus apt and the code CLI for the
+// special tools and VS Code extensions
+const (
+	backendNpm    = "npm"
+	backendUv     = "uv"
+	backendScript = "script"
+	backendApt    = "apt"
+	backendVSCode = "code"
+)
+
+// getInstallBackend returns how a CLI tool or enhancer package is installed
+func getInstallBackend(packageName string) string {
+	switch packageName {
+	case "droid", "goose", "kiro", "plandex":
+		// Installed by vendor curl scripts into the user's home, outside pixi
+		return backendScript
+	case "kimi-cli", "openhands", "specify-cli":
+		return backendUv
+	default:
+		return backendNpm
+	}
+}
+
+// specialToolBackend returns how a special tool is installed
+func specialToolBackend(toolName string) string {
+	switch toolName {
+	case "helm", "lazygit":
+		return backendScript
+	case "modal":
+		return backendUv
+	default:
+		// gh adds its own apt repository first
+		return backendApt
+	}
+}
+
+// categoryBackend returns how a package of a catalog category is installed
+func categoryBackend(category, packageName string) string {
+	switch category {
+	case categoryVSCode:
+		return backendVSCode
+	case categorySpecial:
+		return specialToolBackend(packageName)
+	default:
+		return getInstallBackend(packageName)
+	}
+}
+
+// toolEnvName returns the pixi feature/environment name used to isolate a tool
+func toolEnvName(packageName string) string {
+	name := strings.ToLower(getAliasName(packageName))
+
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// pixiRunArgs builds the arguments for pixi run, selecting a pixi environment when one is given
+func pixiRunArgs(pixiEnv string, args ...string) []string {
+	runArgs := []string{"run"}
+	if pixiEnv != "" {
+		runArgs = append(runArgs, "-e", pixiEnv)
+	}
+	return append(runArgs, args...)
+}
+
+// isolatedUvEnv points uv tool installs at directories owned by the pixi environment,
+// so uv tools in different environments do not share ~/.local/share/uv/tools
+func isolatedUvEnv(envDir, pixiEnv string) []string {
+	return append(os.Environ(),
+		"UV_TOOL_DIR="+filepath.Join(envDir, ".pixi", "uv-tools", pixiEnv),
+		"UV_TOOL_BIN_DIR="+filepath.Join(envDir, ".pixi", "envs", pixiEnv, "bin"),
+	)
+}
+
+// backendRuntime returns the core dependency a backend installs tools with
+func backendRuntime(backend string) string {
+	if backend == backendUv {
+		return "uv"
+	}
+	return "nodejs"
+}
+
+// toolFeatureArgs returns the pixi arguments that add a tool's feature with its runtime
+func toolFeatureArgs(envDir, pixiEnv, spec string) []string {
+	return []string{"add", "--manifest-path", envDir, "--feature", pixiEnv, spec}
+}
+
+// toolEnvironmentArgs returns the pixi arguments that create the environment for a tool's feature
+func toolEnvironmentArgs(envDir, pixiEnv string) []string {
+	return []string{"workspace", "environment", "add", "--manifest-path", envDir, "--feature", pixiEnv, "--force", pixiEnv}
+}
+
+// ensureToolEnvironment creates a pixi feature and environment dedicated to one tool.
+// The environment also includes the default feature, so it inherits the core dependencies
+// but gets its own prefix for npm install -g and uv tool install.
+func ensureToolEnvironment(envDir, pixiEnv, backend string, progress ProgressCallback) error {
+	// Seed the feature with the runtime the backend needs, matching the default feature's spec
+	existing := readPixiDependencies(filepath.Join(envDir, "pixi.toml"))
+	runtime := backendRuntime(backend)
+	spec := CoreDependency{Name: runtime, Version: existing[runtime]}.Spec()
+
+	progress(fmt.Sprintf("Creating isolated pixi environment %s...", pixiEnv))
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("pixi", toolFeatureArgs(envDir, pixiEnv, spec)...)
+	cmd.Stderr = &stderr
+	if err := commandError(cmd.Run(), stderr.Bytes()); err != nil {
+		return fmt.Errorf("could not add feature %s: %v", pixiEnv, err)
+	}
+
+	stderr.Reset()
+	cmd = exec.Command("pixi", toolEnvironmentArgs(envDir, pixiEnv)...)
+	cmd.Stderr = &stderr
+	if err := commandError(cmd.Run(), stderr.Bytes()); err != nil {
+		return fmt.Errorf("could not add environment %s: %v", pixiEnv, err)
+	}
+	return nil
+}
+
+// RemoveIsolatedTool removes a tool's pixi environment and feature, leaving other tools untouched
+func RemoveIsolatedTool(envDir, pixiEnv string, progress ProgressCallback) error {
+	progress(fmt.Sprintf("Removing isolated pixi environment %s...", pixiEnv))
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("pixi", "workspace", "environment", "remove", "--manifest-path", envDir, pixiEnv)
+	cmd.Stderr = &stderr
+	if err := commandError(cmd.Run(), stderr.Bytes()); err != nil {
+		return fmt.Errorf("could not remove environment %s: %v", pixiEnv, err)
+	}
+
+	stderr.Reset()
+	cmd = exec.Command("pixi", "workspace", "feature", "remove", "--manifest-path", envDir, pixiEnv)
+	cmd.Stderr = &stderr
+	if err := commandError(cmd.Run(), stderr.Bytes()); err != nil {
+		return fmt.Errorf("could not remove feature %s: %v", pixiEnv, err)
+	}
+
+	// Drop the installed prefix and uv tool directory
+	os.RemoveAll(filepath.Join(envDir, ".pixi", "envs", pixiEnv))
+	os.RemoveAll(filepath.Join(envDir, ".pixi", "uv-tools", pixiEnv))
+
+	progress(fmt.Sprintf("✓ Removed isolated pixi environment %s", pixiEnv))
+	return nil
+}
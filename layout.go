@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Layout limits, in terminal cells
+const (
+	maxTitleWidth  = 55  // the title box never grows past its original width
+	minTitleWidth  = 20  // nor shrinks so far that the emoji titles break apart
+	maxDetailWidth = 80  // widest detail pane
+	twoColumnWidth = 120 // from this width the detail pane sits beside the list
+	columnGap      = 2
+	minListRows    = 6 // a stacked detail pane is dropped when fewer list rows would remain
+)
+
+// resize adapts the layout to the terminal size. Until the first tea.WindowSizeMsg the
+// size is unknown and the screens render at their natural size.
+func (m *model) resize(width, height int) {
+	m.width, m.height = width, height
+	titleStyle = titleStyle.Width(max(minTitleWidth, min(maxTitleWidth, width-titleStyle.GetHorizontalBorderSize())))
+
+	// Keep the text inputs, including their prompt and cursor, on one line
+	fit := func(input *textinput.Model, natural int) {
+		input.Width = max(10, min(natural, width-len(input.Prompt)-6))
+	}
+	fit(&m.pathInput, 50)
+	fit(&m.envInput, 50)
+	for i := range m.coreInputs {
+		fit(&m.coreInputs[i], 40)
+	}
+	for i := range m.credentialInputs {
+		fit(&m.credentialInputs[i], 50)
+	}
+	fit(&m.aliasInput, 30)
+	fit(&m.profileInput, 30)
+	fit(&m.filterInput, 30)
+}
+
+// wrap renders a paragraph, wrapping it when it is wider than the terminal
+func (m model) wrap(style lipgloss.Style, text string) string {
+	return wrapText(style, text, m.width)
+}
+
+// wrapText renders a paragraph, wrapping it when it is wider than width; 0 means unlimited
+func wrapText(style lipgloss.Style, text string, width int) string {
+	if width > 0 && lipgloss.Width(style.Render(text)) > width {
+		style = style.Width(width)
+	}
+	return style.Render(text)
+}
+
+// truncate cuts a single-line row to the given width, 0 meaning unlimited
+func truncate(row string, width int) string {
+	if width <= 0 {
+		return row
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(row)
+}
+
+// scrollWindow returns the rows [start, end) of count rows that fit in height rows with the
+// cursor row roughly in the middle
+func scrollWindow(cursor, count, height int) (int, int) {
+	if height <= 0 || count <= height {
+		return 0, count
+	}
+	start := max(0, min(cursor-height/2, count-height))
+	return start, start + height
+}
+
+// scrollRows fits single-line rows into height lines so the cursor row stays visible,
+// replacing the first and last line with counts of the rows scrolled out of view. A height
+// of 0 means unlimited.
+func scrollRows(rows []string, cursor, height int) []string {
+	if height <= 0 || len(rows) <= height {
+		return rows
+	}
+	start, end := scrollWindow(cursor, len(rows), max(1, height-2))
+	return markScrolled(rows, start, end)
+}
+
+// scrollLines fits the lines of a page without a cursor into height lines, starting at the
+// given line offset and marking the lines out of view like scrollRows. A height of 0 means
+// unlimited.
+func scrollLines(lines []string, offset, height int) []string {
+	if height <= 0 || len(lines) <= height {
+		return lines
+	}
+	start := max(0, min(offset, maxScrollOffset(len(lines), height)))
+	return markScrolled(lines, start, start+max(1, height-2))
+}
+
+// maxScrollOffset returns the offset at which scrollLines shows the last of count lines
+func maxScrollOffset(count, height int) int {
+	if height <= 0 || count <= height {
+		return 0
+	}
+	return count - max(1, height-2)
+}
+
+// markScrolled returns the rows [start, end) between lines counting the rows above and below
+func markScrolled(rows []string, start, end int) []string {
+	above, below := "", ""
+	if start > 0 {
+		above = uncheckedStyle.Render(fmt.Sprintf("  ↑ %d more", start))
+	}
+	if end < len(rows) {
+		below = uncheckedStyle.Render(fmt.Sprintf("  ↓ %d more", len(rows)-end))
+	}
+	visible := append([]string{above}, rows[start:end]...)
+	return append(visible, below)
+}
+
+// listHeight returns the rows left for a list between a screen's header and footer, both
+// ending in a newline, or 0 when the terminal height is unknown
+func listHeight(height int, header, footer string) int {
+	if height <= 0 {
+		return 0
+	}
+	// The last line stays free so the terminal does not scroll
+	return max(3, height-strings.Count(header, "\n")-strings.Count(footer, "\n")-1)
+}
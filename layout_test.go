@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScrollWindow(t *testing.T) {
+	tests := []struct {
+		cursor, count, height int
+		start, end            int
+	}{
+		{0, 5, 0, 0, 5},    // unknown height shows everything
+		{3, 5, 10, 0, 5},   // everything fits
+		{0, 20, 6, 0, 6},   // top of the list
+		{10, 20, 6, 7, 13}, // cursor in the middle
+		{19, 20, 6, 14, 20},
+		{2, 20, 6, 0, 6},
+		{17, 20, 5, 15, 20},
+	}
+	for _, tt := range tests {
+		start, end := scrollWindow(tt.cursor, tt.count, tt.height)
+		if start != tt.start || end != tt.end {
+			t.Errorf("scrollWindow(%d, %d, %d) = %d, %d, want %d, %d", tt.cursor, tt.count, tt.height, start, end, tt.start, tt.end)
+		}
+		if tt.cursor < start || tt.cursor >= end {
+			t.Errorf("scrollWindow(%d, %d, %d) hides the cursor", tt.cursor, tt.count, tt.height)
+		}
+	}
+}
+
+func TestScrollRows(t *testing.T) {
+	rows := make([]string, 10)
+	for i := range rows {
+		rows[i] = fmt.Sprint(i)
+	}
+	more := func(arrow string, n int) string {
+		return uncheckedStyle.Render(fmt.Sprintf("  %s %d more", arrow, n))
+	}
+
+	tests := []struct {
+		name           string
+		cursor, height int
+		want           []string
+	}{
+		{"unlimited", 5, 0, rows},
+		{"fits", 5, 10, rows},
+		{"top", 0, 5, []string{"", "0", "1", "2", more("↓", 7)}},
+		{"middle", 5, 5, []string{more("↑", 4), "4", "5", "6", more("↓", 3)}},
+		{"bottom", 9, 5, []string{more("↑", 7), "7", "8", "9", ""}},
+	}
+	for _, tt := range tests {
+		got := scrollRows(rows, tt.cursor, tt.height)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: scrollRows = %q, want %q", tt.name, got, tt.want)
+		}
+		if tt.height > 0 && len(got) > tt.height {
+			t.Errorf("%s: %d lines, more than the height %d", tt.name, len(got), tt.height)
+		}
+	}
+}
+
+func TestListHeight(t *testing.T) {
+	tests := []struct {
+		height         int
+		header, footer string
+		want           int
+	}{
+		{0, "\ntitle\n\n", "\nhelp\n", 0},
+		{24, "\ntitle\n\n", "\nhelp\n", 24 - 3 - 2 - 1},
+		{40, "", "", 39},
+		// Tiny terminals still get a few rows
+		{5, "\ntitle\n\n", "\nhelp\n", 3},
+	}
+	for _, tt := range tests {
+		if got := listHeight(tt.height, tt.header, tt.footer); got != tt.want {
+			t.Errorf("listHeight(%d, %q, %q) = %d, want %d", tt.height, tt.header, tt.footer, got, tt.want)
+		}
+	}
+}
+
+func TestScrollLines(t *testing.T) {
+	lines := make([]string, 10)
+	for i := range lines {
+		lines[i] = fmt.Sprint(i)
+	}
+	more := func(arrow string, n int) string {
+		return uncheckedStyle.Render(fmt.Sprintf("  %s %d more", arrow, n))
+	}
+
+	tests := []struct {
+		name           string
+		offset, height int
+		want           []string
+	}{
+		{"unlimited", 3, 0, lines},
+		{"fits", 3, 10, lines},
+		{"top", 0, 5, []string{"", "0", "1", "2", more("↓", 7)}},
+		{"offset", 4, 5, []string{more("↑", 4), "4", "5", "6", more("↓", 3)}},
+		{"last page", 7, 5, []string{more("↑", 7), "7", "8", "9", ""}},
+		{"past the end", 50, 5, []string{more("↑", 7), "7", "8", "9", ""}},
+	}
+	for _, tt := range tests {
+		if got := scrollLines(lines, tt.offset, tt.height); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: scrollLines = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := maxScrollOffset(len(lines), 5); got != 7 {
+		t.Errorf("maxScrollOffset = %d, want 7", got)
+	}
+}
+
+// screenLines returns the rows a rendered screen takes up
+func screenLines(screen string) int {
+	return strings.Count(screen, "\n")
+}
+
+func TestShortTerminalScreens(t *testing.T) {
+	results := []InstallResult{}
+	for i := 0; i < 30; i++ {
+		results = append(results, InstallResult{Name: fmt.Sprintf("tool-%d", i), Error: errors.New("exit status 1"), LogPath: "/logs/tool.log"})
+	}
+	environments := []EnvironmentRecord{}
+	for i := 0; i < 30; i++ {
+		environments = append(environments, EnvironmentRecord{Name: fmt.Sprintf("env-%02d", i), Path: fmt.Sprintf("/nowhere/env-%02d", i)})
+	}
+
+	selected := map[string]bool{}
+	for _, name := range catalogNames(cliToolCatalog) {
+		selected[name] = true
+	}
+
+	for _, height := range []int{24, 30, 40} {
+		m := model{width: 100, height: height, config: defaultConfig(), platform: hostPlatform(),
+			installResults: results, environments: environments, cursor: 25,
+			installPath: "/work", envName: defaultEnvName, selectedCLI: selected, shellTargets: map[string]bool{shellBash: true}}
+
+		screens := map[string]string{
+			"welcome": m.renderWelcome(),
+			"summary": m.renderInstallSummary(),
+			"done":    m.renderDone(),
+		}
+		for name, screen := range screens {
+			// The last row stays free so the terminal does not scroll
+			if lines := screenLines(screen); lines > height-1 {
+				t.Errorf("%s at height %d: %d lines", name, height, lines)
+			}
+		}
+		// The environment under the cursor stays visible
+		if !strings.Contains(screens["welcome"], "env-25") {
+			t.Errorf("welcome at height %d hides the highlighted environment", height)
+		}
+	}
+
+	// Without a known height everything is shown
+	m := model{config: defaultConfig(), platform: hostPlatform(), installResults: results}
+	if !strings.Contains(m.renderDone(), "tool-29") {
+		t.Error("done screen without a height is cut off")
+	}
+}
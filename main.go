@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -13,11 +14,16 @@ type sessionState int
 
 const (
 	welcomeView sessionState = iota
+	coreConfigView
+	profilesView
 	cliToolsView
 	vscodeExtensionsView
 	specialToolsView
 	cliEnhancersView
 	pathInputView
+	credentialsView
+	shellTargetsView
+	aliasNamesView
 	installView
 	installingView
 	doneView
@@ -34,9 +40,46 @@ type model struct {
 	selectedSpecial      map[string]bool
 	cliEnhancers         []string
 	selectedCLIEnhancers map[string]bool
+	filterInput          textinput.Model
+	filtering            bool
 	cursor               int
+	width                int // terminal size, 0 until the first tea.WindowSizeMsg
+	height               int
+	config               Config
+	coreInputs           []textinput.Model
+	coreFocus            int
+	coreErr              error
+	platform             string
 	pathInput            textinput.Model
 	installPath          string
+	envInput             textinput.Model
+	envName              string
+	pathFocus            int
+	pathErr              error
+	environments         []EnvironmentRecord
+	activeEnv            string
+	statusMessage        string
+	confirmRemove        bool
+	isolate              bool
+	credentialVars       []string
+	credentialInputs     []textinput.Model
+	credentialFocus      int
+	savedCredentials     map[string]string
+	shellTargets         map[string]bool
+	shellMode            string
+	loginShell           string
+	installedShells      map[string]bool
+	aliasNames           AliasNames
+	aliasList            []string
+	aliasConflicts       map[string]string
+	aliasInput           textinput.Model
+	editingAlias         bool
+	aliasErr             error
+	profiles             []Profile
+	profileInput         textinput.Model
+	savingProfile        bool
+	profileMessage       string
+	preflight            []Check
 	spinner              spinner.Model
 	installing           bool
 	installMessages      []string
@@ -49,12 +92,21 @@ type installMsgStart struct{}
 type installMsg struct{ message string }
 type installCompleteMsg struct{ results []InstallResult }
 
+// Core configuration input fields
+const (
+	coreNodeField = iota
+	corePythonField
+	coreExtrasField
+	corePlatformsField
+)
+
 func initialModel() model {
-	// Default installation directory
-	currentDir, err := os.Getwd()
-	if err != nil {
-		currentDir = "."
-	}
+	// Load user configuration, falling back to defaults on error
+	cfg, cfgErr := LoadConfig()
+	applyTheme(cfg.UI.Theme)
+
+	// Default installation directory: the configured one or the working directory
+	currentDir := cfg.DefaultInstallPath()
 
 	// Create text input for path
 	ti := textinput.New()
@@ -64,29 +116,121 @@ func initialModel() model {
 	ti.Width = 50
 	ti.SetValue(currentDir)
 
+	// Create text input for the environment name
+	ei := textinput.New()
+	ei.Placeholder = defaultEnvName
+	ei.CharLimit = 64
+	ei.Width = 50
+	ei.SetValue(cfg.DefaultEnvName())
+
+	// Load known environments, falling back to an empty state on error
+	state, stateErr := LoadState()
+	if cfgErr == nil {
+		cfgErr = stateErr
+	}
+
+	// Text input for renaming an alias
+	ai := textinput.New()
+	ai.CharLimit = 64
+	ai.Width = 30
+
+	// Text input for naming a saved profile
+	pi := textinput.New()
+	pi.Placeholder = "team-default"
+	pi.CharLimit = 64
+	pi.Width = 30
+
+	// Create text input for the selection filter
+	fi := textinput.New()
+	fi.Prompt = "/"
+	fi.Placeholder = "name, package or tag"
+	fi.CharLimit = 64
+	fi.Width = 30
+
 	s := spinner.New()
 	s.Spinner = spinner.Points
 	s.Style = spinnerStyle
 
+	// Preselect the configured shells, those chosen last time, or the login shell
+	shells := state.Shells()
+	if len(cfg.UI.ShellTargets) > 0 {
+		shells = cfg.UI.ShellTargets
+	}
+	shellTargets := make(map[string]bool)
+	for _, shell := range shells {
+		shellTargets[shell] = true
+	}
+	shellMode := state.IntegrationMode()
+	if cfg.UI.Mode != "" {
+		shellMode = cfg.UI.Mode
+	}
+
+	// Preselect the configured tools; unknown ones are ignored like in a profile
+	cli, vscode, special, enhancers, _ := cfg.Preselection().resolve(hostPlatform())
+
 	return model{
 		state:                welcomeView,
 		cliTools:             getCLITools(),
-		selectedCLI:          make(map[string]bool),
+		selectedCLI:          selectionSet(cli),
 		vscodeExts:           getVSCodeExtensions(),
-		selectedVSCode:       make(map[string]bool),
+		selectedVSCode:       selectionSet(vscode),
 		specialTools:         getSpecialTools(),
-		selectedSpecial:      make(map[string]bool),
+		selectedSpecial:      selectionSet(special),
 		cliEnhancers:         getCLIEnhancers(),
-		selectedCLIEnhancers: make(map[string]bool),
+		selectedCLIEnhancers: selectionSet(enhancers),
+		filterInput:          fi,
 		cursor:               0,
+		config:               cfg,
+		coreInputs:           newCoreInputs(cfg.Core),
+		platform:             hostPlatform(),
 		pathInput:            ti,
 		installPath:          currentDir,
+		envInput:             ei,
+		envName:              cfg.DefaultEnvName(),
+		environments:         discoverEnvironments(state, currentDir),
+		activeEnv:            state.Active,
+		isolate:              cfg.Install.IsolateTools,
+		shellTargets:         shellTargets,
+		shellMode:            shellMode,
+		loginShell:           detectLoginShell(),
+		installedShells:      detectInstalledShells(),
+		aliasNames:           state.AliasNames.Clone(),
+		aliasInput:           ai,
+		profileInput:         pi,
 		spinner:              s,
 		installMessages:      []string{},
 		installResults:       []InstallResult{},
+		err:                  cfgErr,
 	}
 }
 
+// newCoreInputs creates the text inputs used by the core configuration screen
+func newCoreInputs(core CoreConfig) []textinput.Model {
+	inputs := make([]textinput.Model, 4)
+
+	inputs[coreNodeField] = textinput.New()
+	inputs[coreNodeField].Placeholder = "22.*"
+	inputs[coreNodeField].SetValue(core.NodeVersion)
+
+	inputs[corePythonField] = textinput.New()
+	inputs[corePythonField].Placeholder = "3.12.*"
+	inputs[corePythonField].SetValue(core.PythonVersion)
+
+	inputs[coreExtrasField] = textinput.New()
+	inputs[coreExtrasField].Placeholder = "git, make"
+	inputs[coreExtrasField].SetValue(strings.Join(core.ExtraPackages, ", "))
+
+	inputs[corePlatformsField] = textinput.New()
+	inputs[corePlatformsField].Placeholder = "linux-64, linux-aarch64"
+	inputs[corePlatformsField].SetValue(strings.Join(core.Platforms, ", "))
+
+	for i := range inputs {
+		inputs[i].CharLimit = 256
+		inputs[i].Width = 40
+	}
+	return inputs
+}
+
 func (m model) Init() tea.Cmd {
 	return textinput.Blink
 }
@@ -96,6 +240,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Handle installation messages
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		return m, nil
+
 	case installMsgStart:
 		m.installing = true
 		m.state = installingView
@@ -109,6 +257,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.installing = false
 		m.installResults = msg.results
 		m.state = doneView
+		m.cursor = 0
+		if state, err := LoadState(); err == nil {
+			m.activeEnv = state.Active
+			m.environments = discoverEnvironments(state, m.installPath)
+		}
 		return m, nil
 
 	case spinner.TickMsg:
@@ -119,6 +272,35 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Handle core configuration inputs separately
+	if m.state == coreConfigView {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "ctrl+c":
+				m.state = quitView
+				return m, tea.Quit
+			case "esc":
+				// Discard edits and go back to the welcome view
+				m.coreInputs = newCoreInputs(m.config.Core)
+				m.coreErr = nil
+				m.state = welcomeView
+				return m, nil
+			case "tab", "down":
+				m.focusCoreInput((m.coreFocus + 1) % len(m.coreInputs))
+				return m, nil
+			case "shift+tab", "up":
+				m.focusCoreInput((m.coreFocus + len(m.coreInputs) - 1) % len(m.coreInputs))
+				return m, nil
+			case "enter":
+				return m.saveCoreConfig()
+			}
+		}
+
+		m.coreInputs[m.coreFocus], cmd = m.coreInputs[m.coreFocus].Update(msg)
+		return m, cmd
+	}
+
 	// Handle path input separately
 	if m.state == pathInputView {
 		switch msg := msg.(type) {
@@ -128,21 +310,162 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = quitView
 				return m, tea.Quit
 			case "esc":
-				// Go back to special tools view
-				m.state = specialToolsView
+				// Go back to the last selection screen
+				m.state = m.previousSelectionView(pathInputView)
+				return m, nil
+			case "tab", "shift+tab":
+				// Switch between the parent directory and environment name fields
+				return m, m.focusPathInput(1 - m.pathFocus)
+			case "up", "down":
+				// Cycle through existing environments
+				m.selectExistingEnvironment(msg.String() == "down")
 				return m, nil
 			case "enter":
-				// Validate and accept the path
+				// Validate and accept the path and environment name
 				path := m.pathInput.Value()
+				name := m.envInput.Value()
+				if name == "" {
+					name = defaultEnvName
+				}
+				if err := validateEnvName(name); err != nil {
+					m.pathErr = err
+					return m, nil
+				}
 				if path != "" {
 					m.installPath = path
-					m.state = installView
+					m.envName = name
+					m.pathErr = nil
+					m.cursor = 0
+					return m, m.enterCredentials()
 				}
 				return m, nil
 			}
 		}
 
-		m.pathInput, cmd = m.pathInput.Update(msg)
+		if m.pathFocus == 0 {
+			m.pathInput, cmd = m.pathInput.Update(msg)
+		} else {
+			m.envInput, cmd = m.envInput.Update(msg)
+		}
+		return m, cmd
+	}
+
+	// Handle credential inputs separately
+	if m.state == credentialsView {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "ctrl+c":
+				m.state = quitView
+				return m, tea.Quit
+			case "esc":
+				// Go back to the path step, keeping what was typed
+				m.state = pathInputView
+				return m, m.focusPathInput(0)
+			case "tab", "down":
+				return m, m.focusCredentialInput((m.credentialFocus + 1) % len(m.credentialInputs))
+			case "shift+tab", "up":
+				return m, m.focusCredentialInput((m.credentialFocus + len(m.credentialInputs) - 1) % len(m.credentialInputs))
+			case "ctrl+e":
+				// Copy the value already exported in the current environment
+				name := m.credentialVars[m.credentialFocus]
+				if value := os.Getenv(name); value != "" {
+					m.credentialInputs[m.credentialFocus].SetValue(value)
+				}
+				return m, nil
+			case "enter":
+				m.state = shellTargetsView
+				m.cursor = 0
+				return m, nil
+			}
+		}
+
+		m.credentialInputs[m.credentialFocus], cmd = m.credentialInputs[m.credentialFocus].Update(msg)
+		return m, cmd
+	}
+
+	// Handle the selection filter separately while it has focus
+	if m.filtering {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "ctrl+c":
+				m.state = quitView
+				return m, tea.Quit
+			case "esc":
+				m.clearFilter()
+				return m, nil
+			case "enter":
+				// Keep the filter and go back to the list
+				m.filtering = false
+				m.filterInput.Blur()
+				return m, nil
+			case "up":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+				return m, nil
+			case "down":
+				m.cursor = m.handleDown()
+				return m, nil
+			}
+		}
+
+		query := m.filterInput.Value()
+		m.filterInput, cmd = m.filterInput.Update(msg)
+		if m.filterInput.Value() != query {
+			// Highlight the best match
+			m.cursor = 0
+			if list, _ := m.selectionList(m.state); len(list.items) > 0 {
+				m.cursor = 1
+			}
+		}
+		return m, cmd
+	}
+
+	// Handle alias renaming separately while the name field is open
+	if m.state == aliasNamesView && m.editingAlias {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "ctrl+c":
+				m.state = quitView
+				return m, tea.Quit
+			case "esc":
+				// Discard the edit
+				m.editingAlias = false
+				m.aliasErr = nil
+				m.aliasInput.Blur()
+				return m, nil
+			case "enter":
+				m.renameAlias()
+				return m, nil
+			}
+		}
+
+		m.aliasInput, cmd = m.aliasInput.Update(msg)
+		return m, cmd
+	}
+
+	// Handle profile naming separately while the name field is open
+	if m.state == installView && m.savingProfile {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "ctrl+c":
+				m.state = quitView
+				return m, tea.Quit
+			case "esc":
+				m.savingProfile = false
+				m.profileInput.Blur()
+				return m, nil
+			case "enter":
+				m.saveProfile()
+				return m, nil
+			}
+		}
+
+		m.profileInput, cmd = m.profileInput.Update(msg)
 		return m, cmd
 	}
 
@@ -153,6 +476,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "enter", "q", " ":
 				return m, tea.Quit
+			case "up", "k":
+				// The cursor scrolls the results
+				if m.cursor > 0 {
+					m.cursor--
+				}
+			case "down", "j":
+				m.cursor = m.handleDown()
 			}
 		}
 		return m, nil
@@ -160,28 +490,94 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Any key other than a second x cancels a pending removal
+		if msg.String() != "x" {
+			m.confirmRemove = false
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			m.state = quitView
 			return m, tea.Quit
 
+		case "x":
+			// Remove the managed shell block from the welcome view, asking for confirmation first
+			if m.state == welcomeView {
+				m.removeShellIntegration()
+			}
+
 		case "enter":
 			return m.handleEnter()
 
-		case "up", "k":
-			// Disable cursor navigation in installation summary view
-			if m.state != installView {
-				if m.cursor > 0 {
-					m.cursor--
+		case "c":
+			// Open the core dependency configuration from the welcome view
+			if m.state == welcomeView {
+				m.state = coreConfigView
+				return m, m.focusCoreInput(coreNodeField)
+			}
+
+		case "p":
+			// Choose a profile from the welcome view, or save the selections from the summary
+			switch m.state {
+			case welcomeView:
+				m.openProfiles()
+			case installView:
+				m.profileMessage = ""
+				m.savingProfile = true
+				return m, m.profileInput.Focus()
+			}
+
+		case "i":
+			// Toggle per-tool pixi environment isolation from the installation summary
+			if m.state == installView {
+				m.isolate = !m.isolate
+			}
+
+		case "m":
+			// Toggle between shell aliases and shims on PATH
+			if m.state == shellTargetsView {
+				if m.shellMode == modeShims {
+					m.shellMode = modeAliases
+				} else {
+					m.shellMode = modeShims
 				}
 			}
 
-		case "down", "j":
-			// Disable cursor navigation in installation summary view
-			if m.state != installView {
-				m.cursor = m.handleDown()
+		case "r":
+			// Rename the alias under the cursor
+			if m.state == aliasNamesView && m.cursor < len(m.aliasList) {
+				name, _ := m.aliasNames.Lookup(m.aliasList[m.cursor])
+				if name == "" {
+					name = m.aliasList[m.cursor]
+				}
+				m.aliasInput.SetValue(name)
+				m.aliasInput.CursorEnd()
+				m.editingAlias = true
+				return m, m.aliasInput.Focus()
+			}
+
+		case "/":
+			// Filter the list of a selection screen
+			if _, ok := m.selectionList(m.state); ok {
+				m.filtering = true
+				return m, m.filterInput.Focus()
+			}
+
+		case "s":
+			// Switch the active environment from the welcome view
+			if m.state == welcomeView {
+				m.switchEnvironment()
+			}
+
+		case "up", "k":
+			// In the installation summary the cursor scrolls the overview
+			if m.cursor > 0 {
+				m.cursor--
 			}
 
+		case "down", "j":
+			m.cursor = m.handleDown()
+
 		case " ":
 			// Disable spacebar toggling in installation summary view
 			if m.state != installView {
@@ -191,21 +587,30 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc":
 			// Handle back navigation
 			switch m.state {
-			case cliToolsView:
+			case profilesView:
 				m.state = welcomeView
 				m.cursor = 0
-			case vscodeExtensionsView:
-				m.state = cliToolsView
+			case cliToolsView, vscodeExtensionsView, specialToolsView, cliEnhancersView:
+				// Clear the filter first, then go back
+				if m.filterInput.Value() != "" {
+					m.clearFilter()
+					return m, nil
+				}
+				m.state = m.previousSelectionView(m.state)
 				m.cursor = 0
-			case specialToolsView:
-				m.state = vscodeExtensionsView
+			case shellTargetsView:
 				m.cursor = 0
-			case cliEnhancersView:
-				m.state = specialToolsView
+				if len(m.credentialVars) > 0 {
+					m.state = credentialsView
+					return m, m.focusCredentialInput(m.credentialFocus)
+				}
+				m.state = pathInputView
+				return m, m.focusPathInput(0)
+			case aliasNamesView:
+				m.state = shellTargetsView
 				m.cursor = 0
 			case installView:
-				m.state = pathInputView
-				m.pathInput.Focus()
+				m.state = aliasNamesView
 				m.cursor = 0
 			}
 		}
@@ -218,16 +623,21 @@ func (m model) View() string {
 	switch m.state {
 	case welcomeView:
 		return m.renderWelcome()
-	case cliToolsView:
-		return m.renderCLITools()
-	case vscodeExtensionsView:
-		return m.renderVSCodeExtensions()
-	case specialToolsView:
-		return m.renderSpecialTools()
-	case cliEnhancersView:
-		return m.renderCLIEnhancers()
+	case coreConfigView:
+		return m.renderCoreConfig()
+	case profilesView:
+		return m.renderProfiles()
+	case cliToolsView, vscodeExtensionsView, specialToolsView, cliEnhancersView:
+		list, _ := m.selectionList(m.state)
+		return list.View(m.cursor)
 	case pathInputView:
 		return m.renderPathInput()
+	case credentialsView:
+		return m.renderCredentials()
+	case shellTargetsView:
+		return m.renderShellTargets()
+	case aliasNamesView:
+		return m.renderAliasNames()
 	case installView:
 		return m.renderInstallSummary()
 	case installingView:
@@ -243,6 +653,11 @@ func (m model) View() string {
 var program *tea.Program
 
 func main() {
+	// Subcommands run without the interactive menu
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	program = tea.NewProgram(initialModel())
 	if _, err := program.Run(); err != nil {
 		fmt.Printf("Error: %v", err)
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// condaForgePackages maps the special tools to their conda-forge packages, so a standalone
+// manifest declares them instead of installing them with apt or vendor scripts
+var condaForgePackages = map[string]string{
+	"helm":    "kubernetes-helm",
+	"gh":      "gh",
+	"ripgrep": "ripgrep",
+	"jq":      "jq",
+	"yq":      "go-yq",
+	"bat":     "bat",
+	"exa":     "eza",
+	"fd":      "fd-find",
+	"lazygit": "lazygit",
+}
+
+// installTaskName returns the name of the pixi task that installs a tool
+func installTaskName(packageName string) string {
+	return "install-" + toolEnvName(packageName)
+}
+
+// taskCommand turns an installer command into a pixi task command. Tasks already run inside
+// their environment, so the pixi run prefix is dropped.
+func taskCommand(cmd *exec.Cmd) string {
+	args := cmd.Args
+	if len(args) > 1 && args[0] == "pixi" && args[1] == "run" {
+		args = args[2:]
+		if len(args) > 1 && args[0] == "-e" {
+			args = args[2:]
+		}
+	}
+	return shellCommand(args...)
+}
+
+// manifestWriter builds a pixi.toml; values are written as TOML basic strings
+type manifestWriter struct {
+	bytes.Buffer
+}
+
+// table starts a TOML table
+func (w *manifestWriter) table(name string) {
+	if w.Len() > 0 {
+		w.WriteString("\n")
+	}
+	fmt.Fprintf(w, "[%s]\n", name)
+}
+
+// set writes a key with a string value
+func (w *manifestWriter) set(key, value string) {
+	fmt.Fprintf(w, "%s = %s\n", tomlKey(key), strconv.Quote(value))
+}
+
+// setRaw writes a key with a value that is already TOML
+func (w *manifestWriter) setRaw(key, value string) {
+	fmt.Fprintf(w, "%s = %s\n", tomlKey(key), value)
+}
+
+// tomlKey quotes a key unless it is a bare key
+func tomlKey(key string) string {
+	if key != "" && strings.IndexFunc(key, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+	}) < 0 {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+// tomlArray formats strings as a TOML array
+func tomlArray(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// dependencyVersion returns the manifest version of a core dependency
+func dependencyVersion(dep CoreDependency) string {
+	if dep.Version == "" {
+		return "*"
+	}
+	return dep.Version
+}
+
+// renderPixiManifest returns a self-contained pixi.toml for the plan. Packaged special tools
+// become dependencies, every npm, uv and vendor-script tool gets an install task that the
+// setup task runs, and every command gets a task of its own, so the environment is recreated
+// with `pixi install && pixi run setup`.
+func renderPixiManifest(plan InstallPlan) string {
+	cliTools, specialTools, enhancers := plan.packageNames()
+	tools := append(append([]string{}, cliTools...), enhancers...)
+
+	var w manifestWriter
+	w.WriteString("# Generated by ai-menu export pixi\n")
+	w.WriteString("# Recreate the environment with: pixi install && pixi run setup\n")
+
+	w.table("workspace")
+	w.set("name", plan.EnvName)
+	w.setRaw("channels", tomlArray([]string{"conda-forge"}))
+	w.setRaw("platforms", tomlArray(plan.Core.Platforms))
+
+	w.table("dependencies")
+	for _, dep := range plan.Core.Dependencies() {
+		w.set(dep.Name, dependencyVersion(dep))
+	}
+	for _, tool := range specialTools {
+		if pkg, ok := condaForgePackages[tool]; ok {
+			w.set(pkg, "*")
+		}
+	}
+
+	if containsString(specialTools, "modal") {
+		w.table("pypi-dependencies")
+		w.set("modal", "*")
+	}
+
+	// Global npm and uv tool installs go into the environment's own prefix
+	w.table("activation.env")
+	w.set("UV_TOOL_DIR", "$CONDA_PREFIX/uv-tools")
+	w.set("UV_TOOL_BIN_DIR", "$CONDA_PREFIX/bin")
+	for _, kv := range plan.Registries.Env() {
+		name, value, _ := strings.Cut(kv, "=")
+		w.set(name, value)
+	}
+
+	// Tasks of the default environment, and of each tool's feature in isolation mode
+	tasks := map[string][]string{"": nil}
+	runtimes := map[string]string{}
+	setup := []string{}
+	for _, tool := range tools {
+		var cmd *exec.Cmd
+		if containsString(enhancers, tool) {
+			cmd = enhancerInstallCommand(tool, plan.EnvDir(), "", false)
+		} else {
+			cmd = cliInstallCommand(tool, plan.EnvDir(), "", false)
+		}
+		pixiEnv := plan.toolPixiEnv(tool)
+		name := installTaskName(tool)
+		tasks[pixiEnv] = append(tasks[pixiEnv], tomlKey(name)+" = "+strconv.Quote(taskCommand(cmd)))
+		if pixiEnv == "" {
+			setup = append(setup, strconv.Quote(name))
+		} else {
+			runtimes[pixiEnv] = backendRuntime(getInstallBackend(tool))
+			setup = append(setup, fmt.Sprintf("{ task = %s, environment = %s }", strconv.Quote(name), strconv.Quote(pixiEnv)))
+		}
+	}
+	for _, tool := range plan.toolRecords() {
+		name, enabled := plan.AliasNames.Lookup(tool.Alias)
+		if !enabled {
+			continue
+		}
+		tasks[tool.PixiEnv] = append(tasks[tool.PixiEnv], tomlKey(name)+" = "+strconv.Quote(tool.Command))
+	}
+
+	w.table("tasks")
+	if len(setup) > 0 {
+		w.setRaw("setup", "{ depends-on = ["+strings.Join(setup, ", ")+"] }")
+	} else {
+		w.set("setup", "echo Nothing to install")
+	}
+	for _, task := range tasks[""] {
+		w.WriteString(task + "\n")
+	}
+
+	features := make([]string, 0, len(tasks))
+	for pixiEnv := range tasks {
+		if pixiEnv != "" {
+			features = append(features, pixiEnv)
+		}
+	}
+	sort.Strings(features)
+	for _, feature := range features {
+		w.table("feature." + tomlKey(feature) + ".dependencies")
+		for _, dep := range plan.Core.Dependencies() {
+			if dep.Name == runtimes[feature] {
+				w.set(dep.Name, dependencyVersion(dep))
+			}
+		}
+		w.table("feature." + tomlKey(feature) + ".tasks")
+		for _, task := range tasks[feature] {
+			w.WriteString(task + "\n")
+		}
+	}
+
+	if len(features) > 0 {
+		w.table("environments")
+		for _, feature := range features {
+			w.setRaw(feature, tomlArray([]string{feature}))
+		}
+	}
+	return w.String()
+}
+
+// writePixiProject writes pixi.toml into dir and resolves it into pixi.lock
+func writePixiProject(dir string, plan InstallPlan) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	manifest := filepath.Join(dir, "pixi.toml")
+	if err := os.WriteFile(manifest, []byte(renderPixiManifest(plan)), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✓ Wrote %s\n", manifest)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("pixi", "lock", "--manifest-path", manifest)
+	cmd.Stderr = &stderr
+	if err := commandError(cmd.Run(), stderr.Bytes()); err != nil {
+		return fmt.Errorf("could not create pixi.lock: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "✓ Wrote %s\n", filepath.Join(dir, "pixi.lock"))
+	return nil
+}
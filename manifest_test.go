@@ -0,0 +1,99 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+// lookupPath returns the value at a dotted path of a decoded TOML document, or nil
+func lookupPath(doc map[string]interface{}, path string) interface{} {
+	var value interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = table[key]
+	}
+	return value
+}
+
+func TestRenderPixiManifest(t *testing.T) {
+	base := InstallPlan{
+		InstallPath:  "/work",
+		EnvName:      "team",
+		Core:         CoreConfig{NodeVersion: "22.*", PythonVersion: "3.12.*", Platforms: []string{"linux-64", "osx-arm64"}},
+		CLITools:     []string{"Codex by OpenAI", "Kimi by MoonshotAI"},
+		SpecialTools: []string{"jq - JSON processor", "modal - Serverless cloud platform CLI"},
+	}
+	isolated := base
+	isolated.Isolate = true
+	empty := base
+	empty.CLITools, empty.SpecialTools = nil, nil
+	empty.Registries = RegistryConfig{Npm: "https://npm.example.com", PyPI: "https://pypi.example.com/simple"}
+
+	// Each case checks some dotted paths of the decoded manifest; nil means absent
+	tests := []struct {
+		name string
+		plan InstallPlan
+		want map[string]interface{}
+	}{
+		{"shared environment", base, map[string]interface{}{
+			"workspace.name":             "team",
+			"workspace.channels":         []interface{}{"conda-forge"},
+			"workspace.platforms":        []interface{}{"linux-64", "osx-arm64"},
+			"dependencies.nodejs":        "22.*",
+			"dependencies.python":        "3.12.*",
+			"dependencies.uv":            "*",
+			"dependencies.jq":            "*",
+			"pypi-dependencies.modal":    "*",
+			"activation.env.UV_TOOL_DIR": "$CONDA_PREFIX/uv-tools",
+			"tasks.setup.depends-on":     []interface{}{"install-codex", "install-kimi"},
+			"tasks.install-codex":        "npm install -g @openai/codex",
+			"tasks.install-kimi":         "uv tool install --python 3.13 kimi-cli",
+			"tasks.codex":                "codex",
+			"tasks.modal":                "python -m modal",
+			"environments":               nil,
+		}},
+		{"isolated tools", isolated, map[string]interface{}{
+			"tasks.setup.depends-on": []interface{}{
+				map[string]interface{}{"task": "install-codex", "environment": "codex"},
+				map[string]interface{}{"task": "install-kimi", "environment": "kimi"},
+			},
+			"tasks.install-codex":                nil,
+			"tasks.modal":                        "python -m modal",
+			"feature.codex.dependencies.nodejs":  "22.*",
+			"feature.codex.dependencies.uv":      nil,
+			"feature.codex.tasks.install-codex":  "npm install -g @openai/codex",
+			"feature.codex.tasks.codex":          "codex",
+			"feature.kimi.dependencies.uv":       "*",
+			"feature.kimi.dependencies.nodejs":   nil,
+			"feature.kimi.tasks.install-kimi":    "uv tool install --python 3.13 kimi-cli",
+			"environments.codex":                 []interface{}{"codex"},
+			"environments.kimi":                  []interface{}{"kimi"},
+			"activation.env.NPM_CONFIG_REGISTRY": nil,
+		}},
+		{"registries and nothing to install", empty, map[string]interface{}{
+			"tasks.setup":                        "echo Nothing to install",
+			"activation.env.NPM_CONFIG_REGISTRY": "https://npm.example.com",
+			"activation.env.UV_DEFAULT_INDEX":    "https://pypi.example.com/simple",
+			"pypi-dependencies":                  nil,
+			"environments":                       nil,
+		}},
+	}
+	for _, tt := range tests {
+		var manifest map[string]interface{}
+		if _, err := toml.Decode(renderPixiManifest(tt.plan), &manifest); err != nil {
+			t.Errorf("%s: manifest is not valid TOML: %v", tt.name, err)
+			continue
+		}
+		for path, want := range tt.want {
+			if got := lookupPath(manifest, path); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: %s = %#v, want %#v", tt.name, path, got, want)
+			}
+		}
+	}
+}
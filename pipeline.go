@@ -0,0 +1,222 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// InstallPlan describes one installation run; the TUI and the command line both build one
+type InstallPlan struct {
+	InstallPath      string
+	EnvName          string
+	Core             CoreConfig
+	CLITools         []string // display names from the catalogs
+	VSCodeExtensions []string
+	SpecialTools     []string
+	CLIEnhancers     []string
+	Isolate          bool
+	ToolEnvs         map[string]string // pixi environment per package, overriding Isolate
+	Upgrade          bool              // upgrade tools that are already installed
+	ShellTargets     []string
+	Mode             string
+	AliasNames       AliasNames
+	Credentials      map[string]string
+	Registries       RegistryConfig
+	Concurrency      int // installed tools checked for their version at once
+}
+
+// errCoreDependencies is returned when the pixi environment itself could not be prepared
+var errCoreDependencies = errors.New("failed to ensure core dependencies")
+
+// EnvDir returns the directory of the environment the plan installs into
+func (p InstallPlan) EnvDir() string {
+	return envDirFor(p.InstallPath, p.EnvName)
+}
+
+// IsEmpty reports whether the plan selects no optional tools
+func (p InstallPlan) IsEmpty() bool {
+	return len(p.CLITools) == 0 && len(p.VSCodeExtensions) == 0 && len(p.SpecialTools) == 0 && len(p.CLIEnhancers) == 0
+}
+
+// Items returns every selected catalog item of the plan
+func (p InstallPlan) Items() []string {
+	items := append([]string{}, p.CLITools...)
+	items = append(items, p.VSCodeExtensions...)
+	items = append(items, p.SpecialTools...)
+	return append(items, p.CLIEnhancers...)
+}
+
+// packageNames returns the selected CLI tools, special tools and enhancers by the names the
+// installers receive
+func (p InstallPlan) packageNames() (cliTools, specialTools, enhancers []string) {
+	for _, tool := range p.CLITools {
+		cliTools = append(cliTools, getPackageNameForCLI(tool))
+	}
+	for _, tool := range p.SpecialTools {
+		specialTools = append(specialTools, strings.TrimSpace(strings.Split(tool, " - ")[0]))
+	}
+	for _, enhancer := range p.CLIEnhancers {
+		enhancers = append(enhancers, getPackageNameForCLIEnhancer(enhancer))
+	}
+	return cliTools, specialTools, enhancers
+}
+
+// toolPixiEnv returns the pixi environment a CLI tool or enhancer is installed into, empty
+// for the default environment
+func (p InstallPlan) toolPixiEnv(packageName string) string {
+	if pixiEnv, ok := p.ToolEnvs[packageName]; ok {
+		return pixiEnv
+	}
+	if p.Isolate && getInstallBackend(packageName) != backendScript {
+		return toolEnvName(packageName)
+	}
+	return ""
+}
+
+// toolRecords returns the records a successful installation of the plan would store
+func (p InstallPlan) toolRecords() []ToolRecord {
+	cliTools, specialTools, enhancers := p.packageNames()
+	installed := func(names []string, isolated bool) []InstallResult {
+		results := make([]InstallResult, 0, len(names))
+		for _, name := range names {
+			result := InstallResult{Name: name, Success: true}
+			if isolated {
+				result.Environment = p.toolPixiEnv(name)
+			}
+			results = append(results, result)
+		}
+		return results
+	}
+
+	// Special tools are never isolated
+	records := toolRecordsFor(categoryCLI, installed(cliTools, true))
+	records = append(records, toolRecordsFor(categorySpecial, installed(specialTools, false))...)
+	return append(records, toolRecordsFor(categoryEnhancer, installed(enhancers, true))...)
+}
+
+// CredentialVars returns the environment variables the plan's tools read their API keys from
+func (p InstallPlan) CredentialVars() []string {
+	return credentialVarsFor(p.Items())
+}
+
+// RunInstallation installs the core dependencies and the selected tools, records them in the
+// state file and activates the environment's aliases
+func RunInstallation(plan InstallPlan, progress ProgressCallback) ([]InstallResult, error) {
+	// Collect all results
+	allResults := []InstallResult{}
+
+	// Resolve the target environment directory
+	envDir := plan.EnvDir()
+
+	state, err := LoadState()
+	if err != nil {
+		progress(fmt.Sprintf("⚠️  Could not load ai-menu state, starting fresh: %v", err))
+	}
+
+	// Point npm and uv at the configured registries for every command that follows
+	for _, kv := range plan.Registries.Env() {
+		name, value, _ := strings.Cut(kv, "=")
+		os.Setenv(name, value)
+	}
+
+	// Register the environment so it can be listed and switched to later
+	env, err := state.UpsertEnvironment(plan.EnvName, envDir)
+	if err != nil {
+		progress(fmt.Sprintf("✗ %v", err))
+		return allResults, err
+	}
+	if env.Name != plan.EnvName {
+		progress(fmt.Sprintf("%s is already used by another project; this environment is registered as %s", plan.EnvName, env.Name))
+	}
+
+	// ALWAYS ensure the configured core dependencies first
+	progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	if !EnsureCoreDependencies(envDir, plan.Core, progress) {
+		progress("✗ Failed to ensure core dependencies")
+		return allResults, errCoreDependencies
+	}
+	progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	progress("")
+
+	// Store API keys before the aliases and shims that load them are written
+	if len(plan.Credentials) > 0 {
+		if err := SaveCredentials(envDir, plan.Credentials); err != nil {
+			progress(fmt.Sprintf("⚠️  Could not save credentials: %v", err))
+		} else {
+			progress(fmt.Sprintf("✓ Saved %d credential(s) to %s (readable only by you)", len(plan.Credentials), credentialsPath(envDir)))
+		}
+	}
+
+	// Convert display names to package names for installation
+	cliTools := make([]string, 0, len(plan.CLITools))
+	for _, tool := range plan.CLITools {
+		cliTools = append(cliTools, getPackageNameForCLI(tool))
+	}
+
+	// Perform installations
+	if len(cliTools) > 0 {
+		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		results := InstallCLITools(cliTools, envDir, plan.toolPixiEnv, plan.Upgrade, progress)
+		detectVersions(envDir, results, plan.Concurrency)
+		allResults = append(allResults, results...)
+		env.RecordTools(toolRecordsFor(categoryCLI, results))
+		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		progress("")
+	}
+
+	if len(plan.VSCodeExtensions) > 0 {
+		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		results := InstallVSCodeExtensions(plan.VSCodeExtensions, progress)
+		detectVersions(envDir, results, plan.Concurrency)
+		allResults = append(allResults, results...)
+		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		progress("")
+	}
+
+	if len(plan.SpecialTools) > 0 {
+		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		results := InstallSpecialTools(plan.SpecialTools, envDir, plan.Upgrade, progress)
+		detectVersions(envDir, results, plan.Concurrency)
+		allResults = append(allResults, results...)
+		env.RecordTools(toolRecordsFor(categorySpecial, results))
+
+		// Ubuntu installs bat as batcat, so alias it back in every environment
+		for _, result := range results {
+			if result.Name == "bat" && result.Success {
+				state.SetGlobalAlias("bat", "batcat")
+			}
+		}
+		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		progress("")
+	}
+
+	if len(plan.CLIEnhancers) > 0 {
+		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		results := InstallCLIEnhancers(plan.CLIEnhancers, envDir, plan.toolPixiEnv, plan.Upgrade, progress)
+		detectVersions(envDir, results, plan.Concurrency)
+		allResults = append(allResults, results...)
+		env.RecordTools(toolRecordsFor(categoryEnhancer, results))
+		progress("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		progress("")
+	}
+
+	// Completion scripts are loaded from the managed block, so generate them first
+	GenerateCompletions(*env, plan.ShellTargets, progress)
+
+	// Point the shell aliases at the environment that was just installed into
+	state.ShellTargets = plan.ShellTargets
+	state.Mode = plan.Mode
+	state.AliasNames = plan.AliasNames
+	progress(fmt.Sprintf("Activating aliases for environment %s...", env.Name))
+	if err := ActivateEnvironment(state, env.Name, progress); err != nil {
+		progress(fmt.Sprintf("⚠️  Could not activate aliases: %v", err))
+	} else {
+		for _, shell := range state.Shells() {
+			progress(fmt.Sprintf("Run '%s' or restart %s to use the aliases", sourceHint(shell), shell))
+		}
+	}
+
+	return allResults, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// knownPlatforms lists the pixi platform names ai-menu understands
+var knownPlatforms = []string{
+	"linux-64",
+	"linux-aarch64",
+	"linux-ppc64le",
+	"osx-64",
+	"osx-arm64",
+	"win-64",
+}
+
+// hostPlatform returns the pixi platform name of the machine ai-menu is running on
+func hostPlatform() string {
+	return pixiPlatform(runtime.GOOS, runtime.GOARCH)
+}
+
+// pixiPlatform converts a GOOS/GOARCH pair into a pixi platform name
+func pixiPlatform(goos, goarch string) string {
+	osName := goos
+	switch goos {
+	case "darwin":
+		osName = "osx"
+	case "windows":
+		osName = "win"
+	}
+
+	arch := goarch
+	switch goarch {
+	case "amd64":
+		arch = "64"
+	case "arm64":
+		if goos == "linux" {
+			arch = "aarch64"
+		}
+	}
+
+	return osName + "-" + arch
+}
+
+// defaultPlatforms returns the platforms of a new configuration: linux-64 and linux-aarch64,
+// plus the host platform so the defaults also validate on macOS
+func defaultPlatforms() []string {
+	platforms := []string{"linux-64", "linux-aarch64"}
+	if host := hostPlatform(); isKnownPlatform(host) && !containsString(platforms, host) {
+		platforms = append(platforms, host)
+	}
+	return platforms
+}
+
+// isKnownPlatform reports whether the name is a pixi platform ai-menu understands
+func isKnownPlatform(platform string) bool {
+	for _, p := range knownPlatforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePlatforms checks that every configured platform is known and that the host is included
+func validatePlatforms(platforms []string, host string) error {
+	if len(platforms) == 0 {
+		return fmt.Errorf("no platforms configured")
+	}
+
+	hasHost := false
+	for _, p := range platforms {
+		if !isKnownPlatform(p) {
+			return fmt.Errorf("unknown platform %q (expected one of %s)", p, strings.Join(knownPlatforms, ", "))
+		}
+		if p == host {
+			hasHost = true
+		}
+	}
+
+	if !hasHost {
+		return fmt.Errorf("current platform %s is not in the configured platforms (%s)", host, strings.Join(platforms, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Profile is a named set of selections that can be loaded instead of ticking every screen
+type Profile struct {
+	Name             string   `toml:"name" json:"name"`
+	Description      string   `toml:"description,omitempty" json:"description,omitempty"`
+	CLITools         []string `toml:"cli,omitempty" json:"cli,omitempty"`
+	VSCodeExtensions []string `toml:"vscode,omitempty" json:"vscode,omitempty"`
+	SpecialTools     []string `toml:"special,omitempty" json:"special,omitempty"`
+	CLIEnhancers     []string `toml:"enhancers,omitempty" json:"enhancers,omitempty"`
+	InstallPath      string   `toml:"install_path,omitempty" json:"install_path,omitempty"`
+	EnvName          string   `toml:"env,omitempty" json:"env,omitempty"`
+	Builtin          bool     `toml:"-" json:"builtin"`
+}
+
+// profilesFile is the layout of profiles.toml
+type profilesFile struct {
+	Profiles []Profile `toml:"profile"`
+}
+
+// builtinProfiles returns the presets shipped with ai-menu. Items are resolved like
+// command-line names, so short names such as rg are allowed.
+func builtinProfiles() []Profile {
+	return []Profile{
+		{
+			Name:         "minimal",
+			Description:  "Core dependencies with ripgrep and jq",
+			SpecialTools: []string{"rg", "jq"},
+			Builtin:      true,
+		},
+		{
+			Name:         "OpenAI stack",
+			Description:  "Codex with the GitHub CLI and search/JSON helpers",
+			CLITools:     []string{"codex"},
+			SpecialTools: []string{"gh", "rg", "jq"},
+			Builtin:      true,
+		},
+		{
+			Name:             "everything",
+			Description:      "Every tool available on this platform",
+			CLITools:         getCLITools(),
+			VSCodeExtensions: getVSCodeExtensions(),
+			SpecialTools:     getSpecialTools(),
+			CLIEnhancers:     getCLIEnhancers(),
+			Builtin:          true,
+		},
+	}
+}
+
+// profilesPath returns the location of profiles.toml
+func profilesPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profiles.toml"), nil
+}
+
+// loadSavedProfiles reads the profiles saved by the user
+func loadSavedProfiles() ([]Profile, error) {
+	path, err := profilesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var file profilesFile
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+	return file.Profiles, nil
+}
+
+// LoadProfiles returns the built-in presets followed by the saved profiles. A saved
+// profile with the name of a preset replaces it.
+func LoadProfiles() ([]Profile, error) {
+	saved, err := loadSavedProfiles()
+
+	profiles := []Profile{}
+	for _, preset := range builtinProfiles() {
+		if _, overridden := findProfile(saved, preset.Name); !overridden {
+			profiles = append(profiles, preset)
+		}
+	}
+	return append(profiles, saved...), err
+}
+
+// findProfile looks up a profile by name, ignoring case
+func findProfile(profiles []Profile, name string) (Profile, bool) {
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Name, name) {
+			return profile, true
+		}
+	}
+	return Profile{}, false
+}
+
+// SaveProfile adds the profile to profiles.toml, replacing one with the same name
+func SaveProfile(profile Profile) error {
+	if strings.TrimSpace(profile.Name) == "" {
+		return fmt.Errorf("profile name must not be empty")
+	}
+
+	saved, err := loadSavedProfiles()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range saved {
+		if strings.EqualFold(saved[i].Name, profile.Name) {
+			saved[i] = profile
+			replaced = true
+		}
+	}
+	if !replaced {
+		saved = append(saved, profile)
+	}
+
+	path, err := profilesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Rename a complete temporary file over profiles.toml so an interrupted save never
+	// loses the other profiles
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".profiles.toml-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if err := toml.NewEncoder(tmp).Encode(profilesFile{Profiles: saved}); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// resolve maps the profile's items to catalog display names for each category. Items that
+// are unknown or unavailable on the platform are returned as skipped rather than failing,
+// so a profile saved on another machine still loads.
+func (p Profile) resolve(platform string) (cli, vscode, special, enhancers, skipped []string) {
+	resolveGroup := func(catalog []CatalogEntry, names []string) []string {
+		resolved := []string{}
+		for _, name := range names {
+			items, err := resolveCatalogItems(catalog, []string{name})
+			if err != nil {
+				skipped = append(skipped, name)
+				continue
+			}
+			entry, _ := lookupCatalogEntry(items[0])
+			if !entry.SupportsPlatform(platform) {
+				// The everything preset lists unavailable items on purpose
+				if !p.Builtin {
+					skipped = append(skipped, name)
+				}
+				continue
+			}
+			if !containsString(resolved, items[0]) {
+				resolved = append(resolved, items[0])
+			}
+		}
+		return resolved
+	}
+
+	cli = resolveGroup(cliToolCatalog, p.CLITools)
+	vscode = resolveGroup(vscodeExtensionCatalog, p.VSCodeExtensions)
+	special = resolveGroup(specialToolCatalog, p.SpecialTools)
+	enhancers = resolveGroup(cliEnhancerCatalog, p.CLIEnhancers)
+	return cli, vscode, special, enhancers, skipped
+}
+
+// withoutDisabled drops the items of the categories disabled in the configuration
+func (p Profile) withoutDisabled(cfg Config) Profile {
+	for _, group := range []struct {
+		category string
+		items    *[]string
+	}{
+		{categoryCLI, &p.CLITools},
+		{categoryVSCode, &p.VSCodeExtensions},
+		{categorySpecial, &p.SpecialTools},
+		{categoryEnhancer, &p.CLIEnhancers},
+	} {
+		if !cfg.CategoryEnabled(group.category) {
+			*group.items = nil
+		}
+	}
+	return p
+}
+
+// itemCount returns the number of items the profile selects
+func (p Profile) itemCount() int {
+	return len(p.CLITools) + len(p.VSCodeExtensions) + len(p.SpecialTools) + len(p.CLIEnhancers)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSaveProfile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	steps := []struct {
+		profile Profile
+		want    []string // saved profile names after the step
+	}{
+		{Profile{Name: "team", CLITools: []string{"codex"}}, []string{"team"}},
+		{Profile{Name: "solo", CLITools: []string{"gemini"}}, []string{"team", "solo"}},
+		// Names are matched case-insensitively and replace the saved profile
+		{Profile{Name: "TEAM", CLITools: []string{"kimi"}}, []string{"TEAM", "solo"}},
+	}
+	for _, step := range steps {
+		if err := SaveProfile(step.profile); err != nil {
+			t.Fatal(err)
+		}
+		saved, err := loadSavedProfiles()
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := []string{}
+		for _, profile := range saved {
+			names = append(names, profile.Name)
+		}
+		if !reflect.DeepEqual(names, step.want) {
+			t.Errorf("after saving %s: profiles %v, want %v", step.profile.Name, names, step.want)
+		}
+	}
+
+	// Only profiles.toml is left; no temporary file
+	entries, err := os.ReadDir(filepath.Join(dir, "ai-menu"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "profiles.toml" {
+		t.Errorf("config directory contains %v, want only profiles.toml", entries)
+	}
+
+	if err := SaveProfile(Profile{Name: " "}); err == nil {
+		t.Error("saving a profile without a name succeeded")
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Delimiters of the block ai-menu owns inside each shell startup file
+const (
+	managedBlockBegin = "# >>> ai-menu managed block >>>"
+	managedBlockEnd   = "# <<< ai-menu managed block <<<"
+)
+
+// Markers written by older versions that appended aliases line by line
+var legacyMarkers = []string{
+	"# AI Menu CLI Tool Aliases",
+	"# AI Menu Special Tools Aliases",
+}
+
+// legacyAliasLine matches alias and source lines older versions appended below the markers
+var legacyAliasLine = regexp.MustCompile(`^(alias [^=]+='(pixi run --manifest-path .*|batcat)'|\[ -f ".*/ai-menu/aliases\.[a-z]+" \] && source .*|test -f ".*/ai-menu/aliases\.fish"; and source .*)$`)
+
+// hasLegacyBatAlias reports whether older versions added the bat alias to the content
+func hasLegacyBatAlias(content string) bool {
+	return strings.Contains(content, "# AI Menu Special Tools Aliases") &&
+		strings.Contains(content, "\nalias bat='batcat'\n")
+}
+
+// renderManagedBlock wraps the given lines in the managed block delimiters
+func renderManagedBlock(lines []string) string {
+	var b strings.Builder
+	b.WriteString(managedBlockBegin + "\n")
+	b.WriteString("# Generated by ai-menu from its state file. Do not edit; changes are overwritten.\n")
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString(managedBlockEnd + "\n")
+	return b.String()
+}
+
+// stripManagedBlock removes the managed block and any legacy ai-menu alias sections
+func stripManagedBlock(content string) string {
+	lines := strings.Split(content, "\n")
+	kept := make([]string, 0, len(lines))
+
+	inBlock := false
+	inLegacy := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == managedBlockBegin:
+			inBlock = true
+			continue
+		case inBlock:
+			if trimmed == managedBlockEnd {
+				inBlock = false
+			}
+			continue
+		case isLegacyMarker(trimmed):
+			inLegacy = true
+			// Drop the blank separator older versions wrote before the marker
+			if len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+				kept = kept[:len(kept)-1]
+			}
+			continue
+		case inLegacy && legacyAliasLine.MatchString(trimmed):
+			continue
+		}
+
+		inLegacy = false
+		kept = append(kept, line)
+	}
+
+	return strings.TrimRight(strings.Join(kept, "\n"), "\n")
+}
+
+// isLegacyMarker reports whether the line is a marker written by older versions
+func isLegacyMarker(line string) bool {
+	for _, marker := range legacyMarkers {
+		if line == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceManagedBlock returns content with a freshly rendered block. An existing block is
+// replaced where it stands, so the lines around it keep their order; without one, the block
+// is appended. Legacy alias sections are removed either way.
+func replaceManagedBlock(content string, lines []string) string {
+	block := renderManagedBlock(lines)
+	all := strings.Split(content, "\n")
+	begin, end := managedBlockBounds(all)
+	if begin < 0 {
+		stripped := stripManagedBlock(content)
+		if stripped == "" {
+			return block
+		}
+		return stripped + "\n\n" + block
+	}
+
+	var b strings.Builder
+	if before := stripManagedBlock(strings.Join(all[:begin], "\n")); before != "" {
+		b.WriteString(before + "\n")
+		// Keep the blank line that separated the block from the lines above
+		if strings.TrimSpace(all[begin-1]) == "" {
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(block)
+	if after := stripManagedBlock(strings.Join(all[end+1:], "\n")); after != "" {
+		b.WriteString(after + "\n")
+	}
+	return b.String()
+}
+
+// managedBlockBounds returns the lines holding the delimiters of the first complete managed
+// block, or -1, -1 if there is none
+func managedBlockBounds(lines []string) (int, int) {
+	begin := -1
+	for i, line := range lines {
+		switch trimmed := strings.TrimSpace(line); {
+		case begin < 0 && trimmed == managedBlockBegin:
+			begin = i
+		case begin >= 0 && trimmed == managedBlockEnd:
+			return begin, i
+		}
+	}
+	return -1, -1
+}
+
+// WriteManagedBlock regenerates the managed block in a shell's startup file
+func WriteManagedBlock(shell string, lines []string) (string, error) {
+	rcPath, err := shellRCPath(shell)
+	if err != nil {
+		return "", err
+	}
+
+	return rcPath, writeManagedBlockAt(rcPath, lines)
+}
+
+// writeManagedBlockAt regenerates the managed block in any file, creating it if needed
+func writeManagedBlockAt(path string, lines []string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	updated := replaceManagedBlock(string(existing), lines)
+	return writeRCFile(path, existing, updated)
+}
+
+// RemoveManagedBlock deletes the managed block and legacy ai-menu aliases from a shell's startup file
+func RemoveManagedBlock(shell string) (string, bool, error) {
+	rcPath, err := shellRCPath(shell)
+	if err != nil {
+		return "", false, err
+	}
+
+	existing, err := os.ReadFile(rcPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rcPath, false, nil
+		}
+		return rcPath, false, err
+	}
+
+	updated := stripManagedBlock(string(existing))
+	if updated != "" {
+		updated += "\n"
+	}
+	if updated == string(existing) {
+		return rcPath, false, nil
+	}
+	return rcPath, true, writeRCFile(rcPath, existing, updated)
+}
+
+// writeRCFile backs up the previous content and atomically replaces the startup file.
+// Symlinked rc files (e.g. from dotfile managers) are resolved so the link is preserved.
+func writeRCFile(rcPath string, previous []byte, content string) error {
+	if string(previous) == content {
+		return nil
+	}
+
+	target := rcPath
+	if resolved, err := filepath.EvalSymlinks(rcPath); err == nil {
+		target = resolved
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(target); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	// Keep a copy of the previous version next to the original
+	if len(previous) > 0 {
+		if err := os.WriteFile(rcPath+".ai-menu.bak", previous, mode); err != nil {
+			return fmt.Errorf("could not back up %s: %w", rcPath, err)
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".ai-menu-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, target)
+}
@@ -0,0 +1,80 @@
+package main
+
+import "testing"
+
+func TestStripManagedBlock(t *testing.T) {
+	block := renderManagedBlock([]string{"alias codex='pixi run --manifest-path /work/ai-dev-pixi codex'"})
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"no block", "export PATH=/bin\nalias ll='ls -l'\n", "export PATH=/bin\nalias ll='ls -l'"},
+		{"empty", "", ""},
+		{"block at the end", "export PATH=/bin\n\n" + block, "export PATH=/bin"},
+		{"block in the middle", "export A=1\n" + block + "export B=2\n", "export A=1\nexport B=2"},
+		{"indented delimiters", "export A=1\n  " + managedBlockBegin + "\nalias x='y'\n  " + managedBlockEnd + "\nexport B=2", "export A=1\nexport B=2"},
+		{
+			"legacy cli section",
+			"export A=1\n\n# AI Menu CLI Tool Aliases\nalias codex='pixi run --manifest-path /work/ai-dev-pixi codex'\nalias gemini='pixi run --manifest-path /work/ai-dev-pixi gemini'\nexport B=2\n",
+			"export A=1\nexport B=2",
+		},
+		{
+			"legacy special section",
+			"# AI Menu Special Tools Aliases\nalias bat='batcat'\n",
+			"",
+		},
+		{
+			"legacy source line",
+			"# AI Menu CLI Tool Aliases\n[ -f \"/home/u/.config/ai-menu/aliases.bash\" ] && source \"/home/u/.config/ai-menu/aliases.bash\"\n",
+			"",
+		},
+		{
+			"user aliases after a legacy section are kept",
+			"# AI Menu CLI Tool Aliases\nalias codex='pixi run --manifest-path /work/ai-dev-pixi codex'\nalias ll='ls -l'\n",
+			"alias ll='ls -l'",
+		},
+		{"legacy and managed together", "# AI Menu Special Tools Aliases\nalias bat='batcat'\n" + block, ""},
+	}
+	for _, tt := range tests {
+		if got := stripManagedBlock(tt.content); got != tt.want {
+			t.Errorf("%s: stripManagedBlock = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReplaceManagedBlock(t *testing.T) {
+	old := renderManagedBlock([]string{"alias codex='pixi run --manifest-path /work/old codex'"})
+	lines := []string{"alias codex='pixi run --manifest-path /work/new codex'"}
+	block := renderManagedBlock(lines)
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"empty", "", block},
+		{"no block", "export A=1\n", "export A=1\n\n" + block},
+		{"block at the end", "export A=1\n\n" + old, "export A=1\n\n" + block},
+		// The block stays where it was, e.g. before a direnv hook that must run last
+		{"block in the middle", "export A=1\n" + old + "export B=2\n", "export A=1\n" + block + "export B=2\n"},
+		{"block at the top", old + "\nexport B=2\n", block + "\nexport B=2\n"},
+		{"duplicate block", "export A=1\n" + old + "export B=2\n" + old, "export A=1\n" + block + "export B=2\n"},
+		{
+			"legacy section after the block",
+			old + "# AI Menu Special Tools Aliases\nalias bat='batcat'\nexport B=2\n",
+			block + "export B=2\n",
+		},
+		{"unterminated block", "export A=1\n" + managedBlockBegin + "\nalias x='y'\n", "export A=1\n\n" + block},
+	}
+	for _, tt := range tests {
+		if got := replaceManagedBlock(tt.content, lines); got != tt.want {
+			t.Errorf("%s: replaceManagedBlock = %q, want %q", tt.name, got, tt.want)
+		}
+		// Rewriting with the same lines leaves the file unchanged
+		if got := replaceManagedBlock(tt.want, lines); got != tt.want {
+			t.Errorf("%s: second replaceManagedBlock = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Report formats written by --report
+const (
+	reportJSON  = "json"
+	reportJUnit = "junit"
+)
+
+// versionPattern finds a version number in the output of a --version flag
+var versionPattern = regexp.MustCompile(`\d+\.\d+(\.\d+)?([-+.][0-9A-Za-z.]+)?`)
+
+// installLogDir returns where the output of each installer is kept
+func installLogDir() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "logs"), nil
+}
+
+// saveInstallLog writes the output of a tool's installer to its log file and returns the
+// path, or an empty string when the log could not be written
+func saveInstallLog(name string, stdout, stderr []byte) string {
+	dir, err := installLogDir()
+	if err != nil || os.MkdirAll(dir, 0755) != nil {
+		return ""
+	}
+
+	file := strings.NewReplacer("/", "_", "@", "", " ", "_").Replace(strings.TrimPrefix(name, "@")) + ".log"
+	path := filepath.Join(dir, file)
+	content := append(append([]byte{}, stdout...), stderr...)
+	if os.WriteFile(path, content, 0644) != nil {
+		return ""
+	}
+	return path
+}
+
+// specialToolPrograms lists the commands a special tool may be installed as, in lookup order
+var specialToolPrograms = map[string][]string{
+	"ripgrep": {"rg"},
+	"fd":      {"fdfind", "fd"},
+	"exa":     {"eza", "exa"},
+	"bat":     {"batcat", "bat"},
+}
+
+// programVersion runs a program with --version and extracts the version number
+func programVersion(args ...string) string {
+	output, err := exec.Command(args[0], append(args[1:], "--version")...).Output()
+	if err != nil {
+		return ""
+	}
+	return versionPattern.FindString(string(output))
+}
+
+// pixiOutput runs a command inside the environment and returns its standard output
+func pixiOutput(envDir, pixiEnv string, env []string, args ...string) string {
+	pixiArgs := []string{"run", "--manifest-path", envDir}
+	if pixiEnv != "" {
+		pixiArgs = append(pixiArgs, "-e", pixiEnv)
+	}
+	cmd := exec.Command("pixi", append(pixiArgs, args...)...)
+	cmd.Env = env
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return string(output)
+}
+
+// detectVersion returns the installed version of a successfully installed tool, or an empty
+// string when it cannot be determined
+func detectVersion(envDir string, result InstallResult) string {
+	var uvEnv []string
+	if result.Environment != "" {
+		uvEnv = isolatedUvEnv(envDir, result.Environment)
+	}
+
+	switch {
+	case result.Category == categoryVSCode:
+		// code prints one publisher.name@version line per extension
+		output, err := exec.Command("code", "--list-extensions", "--show-versions").Output()
+		if err != nil {
+			return ""
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			if id, version, found := strings.Cut(strings.TrimSpace(line), "@"); found && strings.EqualFold(id, result.Name) {
+				return version
+			}
+		}
+	case result.Name == "modal":
+		output := pixiOutput(envDir, result.Environment, nil, "uv", "pip", "show", "modal")
+		for _, line := range strings.Split(output, "\n") {
+			if version, found := strings.CutPrefix(line, "Version:"); found {
+				return strings.TrimSpace(version)
+			}
+		}
+	case result.Backend == backendNpm:
+		var listing struct {
+			Dependencies map[string]struct {
+				Version string `json:"version"`
+			} `json:"dependencies"`
+		}
+		name := npmPackageName(result.Name)
+		output := pixiOutput(envDir, result.Environment, nil, "npm", "ls", "-g", "--depth=0", "--json", name)
+		if json.Unmarshal([]byte(output), &listing) == nil {
+			return listing.Dependencies[name].Version
+		}
+	case result.Backend == backendUv:
+		// uv tool list prints "name vX.Y.Z" followed by the tool's executables
+		output := pixiOutput(envDir, result.Environment, uvEnv, "uv", "tool", "list")
+		for _, line := range strings.Split(output, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 && fields[0] == result.Name {
+				return strings.TrimPrefix(fields[1], "v")
+			}
+		}
+	case result.Category == categorySpecial:
+		programs, ok := specialToolPrograms[result.Name]
+		if !ok {
+			programs = []string{result.Name}
+		}
+		for _, program := range programs {
+			if _, err := exec.LookPath(program); err == nil {
+				return programVersion(program)
+			}
+		}
+	default:
+		// Vendor scripts install into the user's PATH
+		return programVersion(getCommandName(result.Name))
+	}
+	return ""
+}
+
+// detectVersions fills in the installed version of every successful result, checking up to
+// concurrency tools at once
+func detectVersions(envDir string, results []InstallResult, concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range results {
+		if !results[i].Success {
+			continue
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(result *InstallResult) {
+			defer wg.Done()
+			result.Version = detectVersion(envDir, *result)
+			<-slots
+		}(&results[i])
+	}
+	wg.Wait()
+}
+
+// Report is the machine-readable summary of an installation, upgrade or removal
+type Report struct {
+	Environment string        `json:"environment,omitempty"`
+	Path        string        `json:"path,omitempty"`
+	StartedAt   time.Time     `json:"started_at"`
+	Duration    float64       `json:"duration_seconds"`
+	Succeeded   int           `json:"succeeded"`
+	Failed      int           `json:"failed"`
+	Results     []ReportEntry `json:"results"`
+}
+
+// ReportEntry describes the outcome for one tool
+type ReportEntry struct {
+	Name     string  `json:"name"`
+	Category string  `json:"category,omitempty"`
+	Backend  string  `json:"backend,omitempty"`
+	Success  bool    `json:"success"`
+	Version  string  `json:"version,omitempty"`
+	Duration float64 `json:"duration_seconds"`
+	Error    string  `json:"error,omitempty"`
+	Message  string  `json:"message"`
+	PixiEnv  string  `json:"pixi_env,omitempty"`
+	LogPath  string  `json:"log_path,omitempty"`
+}
+
+// NewReport summarizes results collected since started
+func NewReport(envName, envPath string, started time.Time, results []InstallResult) Report {
+	report := Report{
+		Environment: envName,
+		Path:        envPath,
+		StartedAt:   started.UTC(),
+		Duration:    time.Since(started).Seconds(),
+		Results:     make([]ReportEntry, 0, len(results)),
+	}
+	for _, result := range results {
+		entry := ReportEntry{
+			Name:     result.Name,
+			Category: result.Category,
+			Backend:  result.Backend,
+			Success:  result.Success,
+			Version:  result.Version,
+			Duration: result.Duration.Seconds(),
+			Message:  result.Message,
+			PixiEnv:  result.Environment,
+			LogPath:  result.LogPath,
+		}
+		if result.Error != nil {
+			entry.Error = result.Error.Error()
+		}
+		if result.Success {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, entry)
+	}
+	return report
+}
+
+// JUnit XML layout understood by CI systems: one test suite per category and one test case per tool
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitSeconds formats a duration for JUnit time attributes
+func junitSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+// junit converts the report to JUnit test suites
+func (r Report) junit() junitTestSuites {
+	suites := junitTestSuites{Name: "ai-menu", Time: junitSeconds(r.Duration)}
+	index := make(map[string]int)
+	seconds := make(map[string]float64)
+	for _, entry := range r.Results {
+		category := entry.Category
+		if category == "" {
+			category = "tools"
+		}
+		i, exists := index[category]
+		if !exists {
+			i = len(suites.Suites)
+			index[category] = i
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: "ai-menu." + category, Timestamp: r.StartedAt.Format(time.RFC3339)})
+		}
+		suite := &suites.Suites[i]
+
+		details := []string{}
+		if entry.Backend != "" {
+			details = append(details, "backend: "+entry.Backend)
+		}
+		if entry.Version != "" {
+			details = append(details, "version: "+entry.Version)
+		}
+		if entry.LogPath != "" {
+			details = append(details, "log: "+entry.LogPath)
+		}
+		testCase := junitTestCase{
+			Name:      entry.Name,
+			ClassName: "ai-menu." + category,
+			Time:      junitSeconds(entry.Duration),
+			SystemOut: strings.Join(details, "\n"),
+		}
+		if !entry.Success {
+			testCase.Failure = &junitFailure{Message: entry.Error, Text: entry.Message}
+			suite.Failures++
+			suites.Failures++
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+		suites.Tests++
+		seconds[category] += entry.Duration
+		suite.Time = junitSeconds(seconds[category])
+	}
+	return suites
+}
+
+// reportFormat returns the format to write, inferring JUnit from an .xml file name
+func reportFormat(path, format string) (string, error) {
+	switch format {
+	case "":
+		if strings.EqualFold(filepath.Ext(path), ".xml") {
+			return reportJUnit, nil
+		}
+		return reportJSON, nil
+	case reportJSON, reportJUnit:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown report format %q (want json or junit)", format)
+}
+
+// encodeReport writes the report in the given format
+func encodeReport(w io.Writer, report Report, format string) error {
+	if format == reportJUnit {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		encoder := xml.NewEncoder(w)
+		encoder.Indent("", "  ")
+		if err := encoder.Encode(report.junit()); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteReport writes the report to a file, or to stdout when path is "-"
+func WriteReport(path, format string, report Report, stdout io.Writer) error {
+	format, err := reportFormat(path, format)
+	if err != nil {
+		return err
+	}
+	if path == "-" {
+		return encodeReport(stdout, report, format)
+	}
+
+	var buf bytes.Buffer
+	if err := encodeReport(&buf, report, format); err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestEncodeReportJUnit(t *testing.T) {
+	report := Report{
+		Environment: "ai-dev-pixi",
+		StartedAt:   time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		Duration:    12.5,
+		Succeeded:   2,
+		Failed:      1,
+		Results: []ReportEntry{
+			{Name: "@openai/codex", Category: categoryCLI, Backend: backendNpm, Success: true, Version: "0.46.0", Duration: 4.25, Message: "installed"},
+			{Name: "kimi-cli", Category: categoryCLI, Backend: backendUv, Duration: 1.5, Error: "exit status 1: no matching distribution", Message: "Failed to install kimi-cli", LogPath: "/logs/kimi-cli.log"},
+			{Name: "jq", Category: categorySpecial, Success: true, Duration: 0.1234},
+			{Name: "legacy", Success: true},
+		},
+	}
+
+	want := xml.Header + `<testsuites name="ai-menu" tests="4" failures="1" time="12.500">
+  <testsuite name="ai-menu.cli" tests="2" failures="1" time="5.750" timestamp="2026-10-18T12:00:00Z">
+    <testcase name="@openai/codex" classname="ai-menu.cli" time="4.250">
+      <system-out>backend: npm&#xA;version: 0.46.0</system-out>
+    </testcase>
+    <testcase name="kimi-cli" classname="ai-menu.cli" time="1.500">
+      <failure message="exit status 1: no matching distribution">Failed to install kimi-cli</failure>
+      <system-out>backend: uv&#xA;log: /logs/kimi-cli.log</system-out>
+    </testcase>
+  </testsuite>
+  <testsuite name="ai-menu.special" tests="1" failures="0" time="0.123" timestamp="2026-10-18T12:00:00Z">
+    <testcase name="jq" classname="ai-menu.special" time="0.123"></testcase>
+  </testsuite>
+  <testsuite name="ai-menu.tools" tests="1" failures="0" time="0.000" timestamp="2026-10-18T12:00:00Z">
+    <testcase name="legacy" classname="ai-menu.tools" time="0.000"></testcase>
+  </testsuite>
+</testsuites>
+`
+
+	var out bytes.Buffer
+	if err := encodeReport(&out, report, reportJUnit); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != want {
+		t.Errorf("JUnit report:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestReportFormat(t *testing.T) {
+	tests := []struct {
+		path, format string
+		want         string
+		wantErr      bool
+	}{
+		{"report.json", "", reportJSON, false},
+		{"report.xml", "", reportJUnit, false},
+		{"REPORT.XML", "", reportJUnit, false},
+		{"-", "", reportJSON, false},
+		{"report.xml", reportJSON, reportJSON, false},
+		{"-", reportJUnit, reportJUnit, false},
+		{"report.txt", "yaml", "", true},
+	}
+	for _, tt := range tests {
+		got, err := reportFormat(tt.path, tt.format)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("reportFormat(%q, %q) = %q, %v, want %q, wantErr %v", tt.path, tt.format, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// selectionScreen describes a tool selection screen: the category it shows and the text
+// around its list. Adding a category takes an entry here and a case in categoryItems.
+type selectionScreen struct {
+	view     sessionState
+	category string
+	title    string
+	intro    []string // explanation lines shown under the title
+}
+
+// selectionScreens lists the tool selection screens in order with the category each one shows
+var selectionScreens = []selectionScreen{
+	{view: cliToolsView, category: categoryCLI, title: "🚀 Select CLI Tools to Install"},
+	{view: vscodeExtensionsView, category: categoryVSCode, title: "🔌 Select VS Code Extensions to Install"},
+	{view: specialToolsView, category: categorySpecial, title: "🔧 Select Special Tools to Install",
+		intro: []string{"These are tools that are commonly used in prompts to aid the AI CLI tool to do various\nthings. In some examples some of these tools are used by various MCP servers.\nAnd some are just some of Scott's favorites."}},
+	{view: cliEnhancersView, category: categoryEnhancer, title: "🔧 Select CLI Tool Enhancers",
+		intro: []string{
+			"These are additional tools that enhance your AI CLI vibe coding and extend functionality.",
+			"Keep in mind you can only have one tool installed at a time as they clobber each other's functionality.",
+			"If you want to try a different CLI enhancer, install it into its own named environment in the next step and switch between environments from the welcome screen.",
+		}},
+}
+
+// selectionListItem is one row of a selection list
+type selectionListItem struct {
+	name        string   // catalog display name, the key of the selection map
+	label       string   // shown with the item style
+	description string   // shown dimmed after the label
+	badges      []string // short notes such as the credentials the tool needs
+	disabled    bool     // cannot be selected, e.g. unavailable on this platform
+	search      []string // what the filter matches: name, package, install backend and tags
+}
+
+// selectionList is the checkbox list of a selection screen: a Select All row at cursor 0
+// followed by one row per visible item. The selection map is shared with the model.
+type selectionList struct {
+	screen    selectionScreen
+	items     []selectionListItem // the items matching the filter, best match first
+	total     int                 // number of items before filtering
+	selected  map[string]bool
+	filter    textinput.Model
+	filtering bool   // the filter input has focus
+	details   string // detail pane of the item under the cursor
+	width     int    // terminal size, 0 when unknown
+	height    int
+	twoColumn bool // the detail pane sits beside the list
+}
+
+// categoryItems returns the catalog items of a category and the model's selection of them
+func (m model) categoryItems(category string) ([]string, map[string]bool) {
+	switch category {
+	case categoryCLI:
+		return m.cliTools, m.selectedCLI
+	case categoryVSCode:
+		return m.vscodeExts, m.selectedVSCode
+	case categorySpecial:
+		return m.specialTools, m.selectedSpecial
+	case categoryEnhancer:
+		return m.cliEnhancers, m.selectedCLIEnhancers
+	}
+	return nil, nil
+}
+
+// selectionList returns the list of a selection screen
+func (m model) selectionList(view sessionState) (selectionList, bool) {
+	for _, screen := range selectionScreens {
+		if screen.view != view {
+			continue
+		}
+		names, selected := m.categoryItems(screen.category)
+		list := selectionList{screen: screen, total: len(names), selected: selected, filter: m.filterInput, filtering: m.filtering,
+			width: m.width, height: m.height, twoColumn: m.width >= twoColumnWidth}
+		for _, name := range names {
+			list.items = append(list.items, m.selectionListItem(screen.category, name))
+		}
+		if query := strings.TrimSpace(m.filterInput.Value()); query != "" {
+			list.items = filterItems(query, list.items)
+		}
+		if m.cursor > 0 && m.cursor <= len(list.items) {
+			list.details = m.toolDetails(screen.category, list.items[m.cursor-1].name, list.detailWidth())
+		}
+		return list, true
+	}
+	return selectionList{}, false
+}
+
+// selectionListItem describes a catalog item for the list: "gh - GitHub CLI" is shown as
+// the label gh with the description GitHub CLI, badged with the credentials it needs
+func (m model) selectionListItem(category, name string) selectionListItem {
+	item := selectionListItem{name: name, label: name, search: []string{name}}
+	if label, description, ok := strings.Cut(name, " - "); ok {
+		item.label, item.description = label, description
+	}
+	if entry, ok := lookupCatalogEntry(name); ok {
+		if len(entry.Credentials) > 0 {
+			item.badges = append(item.badges, "needs "+strings.Join(entry.Credentials, ", "))
+		}
+		item.search = append(item.search, entry.Package, categoryBackend(category, entry.Package))
+		item.search = append(item.search, entry.Tags...)
+	}
+	if !m.isAvailable(name) {
+		item.disabled = true
+		item.badges = append(item.badges, "unavailable on "+m.platform)
+	}
+	return item
+}
+
+// filterItems keeps the items with a field that fuzzy-matches the query, best match first
+func filterItems(query string, items []selectionListItem) []selectionListItem {
+	type scoredItem struct {
+		item  selectionListItem
+		score int
+	}
+	matches := []scoredItem{}
+	for _, item := range items {
+		// Find returns the best matching field first
+		if found := fuzzy.Find(query, item.search); len(found) > 0 {
+			matches = append(matches, scoredItem{item, found[0].Score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	filtered := make([]selectionListItem, 0, len(matches))
+	for _, match := range matches {
+		filtered = append(filtered, match.item)
+	}
+	return filtered
+}
+
+// maxCursor is the cursor position of the last row
+func (l selectionList) maxCursor() int {
+	return len(l.items)
+}
+
+// allSelected reports whether every selectable item is selected
+func (l selectionList) allSelected() bool {
+	selectable := 0
+	for _, item := range l.items {
+		if item.disabled {
+			continue
+		}
+		if !l.selected[item.name] {
+			return false
+		}
+		selectable++
+	}
+	return selectable > 0
+}
+
+// toggle flips the row under the cursor. Select All selects every visible available item, or
+// clears the visible items when they are all selected already; disabled items cannot be
+// toggled. Items hidden by the filter keep their selection.
+func (l selectionList) toggle(cursor int) {
+	if cursor == 0 {
+		if l.allSelected() {
+			for _, item := range l.items {
+				delete(l.selected, item.name)
+			}
+			return
+		}
+		for _, item := range l.items {
+			if !item.disabled {
+				l.selected[item.name] = true
+			}
+		}
+		return
+	}
+	if cursor > len(l.items) {
+		return
+	}
+	item := l.items[cursor-1]
+	if item.disabled {
+		return
+	}
+	if l.selected[item.name] {
+		delete(l.selected, item.name)
+	} else {
+		l.selected[item.name] = true
+	}
+}
+
+// checkbox renders a row's cursor, box and label. Disabled rows are greyed out.
+func checkbox(cursor, checked, disabled bool, label string) string {
+	pointer := " "
+	itemStyle := normalItemStyle
+	if cursor {
+		pointer = ">"
+		itemStyle = selectedItemStyle
+	}
+	box, checkStyle := "[ ]", uncheckedStyle
+	if checked {
+		box, checkStyle = "[✓]", checkedStyle
+	}
+	if disabled {
+		checkStyle, itemStyle = disabledItemStyle, disabledItemStyle
+	}
+	return fmt.Sprintf("%s %s %s", pointer, checkStyle.Render(box), itemStyle.Render(label))
+}
+
+// detailWidth returns the outer width of the detail pane
+func (l selectionList) detailWidth() int {
+	switch {
+	case l.twoColumn:
+		return min(maxDetailWidth, l.width/2)
+	case l.width > 0:
+		return min(maxDetailWidth, l.width)
+	}
+	return maxDetailWidth
+}
+
+// View renders the screen with the cursor on the given row. The list scrolls to keep the
+// cursor visible, and the detail pane goes beside it on wide terminals or below it
+// otherwise, where it gives way to the list when the terminal is short.
+func (l selectionList) View(cursor int) string {
+	help := "↑/k up • ↓/j down • space toggle • / filter • enter next • esc back • q quit"
+	switch {
+	case l.filtering:
+		help = "type to filter • ↑/↓ move • enter done • esc clear filter"
+	case l.filter.Value() != "":
+		help = "↑/k up • ↓/j down • space toggle • / edit filter • esc clear filter • enter next • q quit"
+	}
+	footer := "\n" + wrapText(helpStyle, help, l.width) + "\n"
+
+	header := l.header(true)
+	if l.height > 0 && listHeight(l.height, header, footer) < minListRows {
+		// Short terminals keep the room for the list
+		header = l.header(false)
+	}
+
+	listWidth := l.width
+	if l.twoColumn && l.details != "" {
+		listWidth = l.width - l.detailWidth() - columnGap
+	}
+
+	selectAll := "Select All"
+	if l.filter.Value() != "" {
+		selectAll = "Select All Matches"
+	}
+	rows := []string{checkbox(cursor == 0, l.allSelected(), false, selectAll), ""}
+	for i, item := range l.items {
+		row := checkbox(cursor == i+1, l.selected[item.name], item.disabled, item.label)
+		if item.description != "" {
+			row += uncheckedStyle.Render(" - " + item.description)
+		}
+		for _, badge := range item.badges {
+			row += " " + uncheckedStyle.Render("("+badge+")")
+		}
+		rows = append(rows, truncate(row, listWidth))
+	}
+	if len(l.items) == 0 {
+		rows = append(rows, uncheckedStyle.Render("  No matches"))
+	}
+	// Row 1 is the blank line below Select All
+	cursorRow := cursor
+	if cursor > 0 {
+		cursorRow++
+	}
+
+	height := listHeight(l.height, header, footer)
+	var body string
+	switch {
+	case l.details == "":
+		body = strings.Join(scrollRows(rows, cursorRow, height), "\n")
+	case l.twoColumn:
+		list := strings.Join(scrollRows(rows, cursorRow, height), "\n")
+		body = lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.NewStyle().Width(listWidth).Render(list), strings.Repeat(" ", columnGap), l.details)
+		if height > 0 {
+			body = lipgloss.NewStyle().MaxHeight(height).Render(body)
+		}
+	default:
+		// The pane and the blank line above it
+		paneHeight := lipgloss.Height(l.details) + 1
+		if height > 0 && height-paneHeight < minListRows {
+			body = strings.Join(scrollRows(rows, cursorRow, height), "\n")
+			break
+		}
+		if height > 0 {
+			height -= paneHeight
+		}
+		body = strings.Join(scrollRows(rows, cursorRow, height), "\n") + "\n\n" + l.details
+	}
+
+	return header + body + "\n" + footer
+}
+
+// header renders the title, the optional explanation and the filter field
+func (l selectionList) header(intro bool) string {
+	var b strings.Builder
+
+	// Add top padding
+	b.WriteString("\n")
+
+	b.WriteString(titleStyle.Render(l.screen.title))
+	b.WriteString("\n\n")
+
+	if intro && len(l.screen.intro) > 0 {
+		for _, line := range l.screen.intro {
+			b.WriteString(wrapText(helpStyle, line, l.width))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if l.filtering || l.filter.Value() != "" {
+		b.WriteString(l.filter.View())
+		b.WriteString(uncheckedStyle.Render(fmt.Sprintf("  %d of %d", len(l.items), l.total)))
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// selectedNames returns the sorted names in a selection map
+func selectedNames(selected map[string]bool) []string {
+	names := []string{}
+	for name, ok := range selected {
+		if ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSelectionListToggle(t *testing.T) {
+	// The list holds the visible items; hidden is selected but filtered out of view
+	items := []selectionListItem{
+		{name: "a"},
+		{name: "b"},
+		{name: "off", disabled: true},
+	}
+
+	tests := []struct {
+		name     string
+		selected []string
+		cursor   int
+		want     []string
+	}{
+		{"select all skips disabled rows", nil, 0, []string{"a", "b"}},
+		{"select all completes a partial selection", []string{"a"}, 0, []string{"a", "b"}},
+		{"select all clears when all are selected", []string{"a", "b"}, 0, []string{}},
+		{"select all keeps hidden items", []string{"hidden"}, 0, []string{"a", "b", "hidden"}},
+		{"clearing keeps hidden items", []string{"a", "b", "hidden"}, 0, []string{"hidden"}},
+		{"select an item", nil, 2, []string{"b"}},
+		{"deselect an item", []string{"a", "b"}, 1, []string{"b"}},
+		{"disabled item cannot be toggled", nil, 3, []string{}},
+		{"cursor past the end", []string{"a"}, 4, []string{"a"}},
+	}
+	for _, tt := range tests {
+		list := selectionList{items: items, selected: map[string]bool{}}
+		for _, name := range tt.selected {
+			list.selected[name] = true
+		}
+		list.toggle(tt.cursor)
+		if got := selectedNames(list.selected); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: selected %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSelectionListAllSelected(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []selectionListItem
+		selected []string
+		want     bool
+	}{
+		{"all selectable items selected", []selectionListItem{{name: "a"}, {name: "b"}, {name: "off", disabled: true}}, []string{"a", "b"}, true},
+		{"one item missing", []selectionListItem{{name: "a"}, {name: "b"}}, []string{"a"}, false},
+		{"hidden selections do not count", []selectionListItem{{name: "a"}}, []string{"hidden"}, false},
+		{"only disabled items", []selectionListItem{{name: "off", disabled: true}}, nil, false},
+		{"no items", nil, nil, false},
+	}
+	for _, tt := range tests {
+		list := selectionList{items: tt.items, selected: map[string]bool{}}
+		for _, name := range tt.selected {
+			list.selected[name] = true
+		}
+		if got := list.allSelected(); got != tt.want {
+			t.Errorf("%s: allSelected() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFilterItems(t *testing.T) {
+	items := []selectionListItem{
+		{name: "codex", search: []string{"codex - OpenAI Codex CLI", "@openai/codex", "npm", "openai"}},
+		{name: "gemini", search: []string{"gemini - Google Gemini CLI", "@google/gemini-cli", "npm", "google"}},
+		{name: "kimi", search: []string{"kimi - Kimi CLI", "kimi-cli", "uv"}},
+		{name: "jq", search: []string{"jq"}},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"codex", []string{"codex"}},
+		{"uv", []string{"kimi"}},
+		{"npm", []string{"codex", "gemini"}},
+		{"google", []string{"gemini"}},
+		{"gmn", []string{"gemini"}},
+		// Each field is matched on its own, not the fields run together
+		{"jqnpm", []string{}},
+		{"zzz", []string{}},
+	}
+	for _, tt := range tests {
+		got := []string{}
+		for _, item := range filterItems(tt.query, items) {
+			got = append(got, item.name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("filterItems(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFilterItemsBestMatchFirst(t *testing.T) {
+	items := []selectionListItem{
+		{name: "loose", search: []string{"c-o-d-e-x tools"}},
+		{name: "exact", search: []string{"codex"}},
+	}
+	got := filterItems("codex", items)
+	if len(got) != 2 || got[0].name != "exact" {
+		t.Errorf("filterItems ranked %v, want the exact match first", got)
+	}
+}
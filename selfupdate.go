@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Build information, stamped at build time with
+// -ldflags "-X main.version=v1.2.3 -X main.commit=abc1234 -X main.buildDate=2025-01-01T00:00:00Z"
+var (
+	version   = "dev"
+	commit    = ""
+	buildDate = ""
+)
+
+// releaseAPI is the GitHub API endpoint of the repository that publishes the releases;
+// AI_MENU_RELEASE_API points self-update at a mirror or a local stand-in
+const releaseAPI = "https://api.github.com/repos/smpnet74/ai-menu"
+
+// releaseAssetPrefix is the name the release workflow gives the binaries, e.g. aimenu-linux-amd64
+const releaseAssetPrefix = "aimenu"
+
+// VersionInfo describes the running binary
+type VersionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// currentVersion returns the stamped build information, falling back to the VCS details Go
+// records for builds without -ldflags
+func currentVersion() VersionInfo {
+	info := VersionInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		// go install ...@v1.2.3 records the tag; pseudo-versions of local builds stay dev
+		if v := build.Main.Version; info.Version == "dev" && strings.HasPrefix(v, "v") && !strings.ContainsAny(v, "-+") {
+			info.Version = v
+		}
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+	if len(info.Commit) > 12 {
+		info.Commit = info.Commit[:12]
+	}
+	return info
+}
+
+// String formats the version for ai-menu version
+func (v VersionInfo) String() string {
+	details := []string{}
+	if v.Commit != "" {
+		details = append(details, "commit "+v.Commit)
+	}
+	if v.BuildDate != "" {
+		details = append(details, "built "+v.BuildDate)
+	}
+	details = append(details, v.GoVersion, v.Platform)
+	return fmt.Sprintf("ai-menu %s (%s)", v.Version, strings.Join(details, ", "))
+}
+
+// runVersion prints the version of the running binary
+func runVersion(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	jsonOutput := fs.Bool("json", false, "print JSON")
+	if code, stop := parseCommandFlags(fs, args); stop {
+		return code
+	}
+
+	info := currentVersion()
+	if *jsonOutput {
+		writeJSON(out, info)
+		return exitOK
+	}
+	fmt.Fprintln(out, info)
+	return exitOK
+}
+
+// Release is the part of a GitHub release that self-update needs
+type Release struct {
+	TagName string         `json:"tag_name"`
+	Assets  []ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset is a file attached to a release
+type ReleaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// asset finds an attached file by name
+func (r Release) asset(name string) (ReleaseAsset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return ReleaseAsset{}, false
+}
+
+// releaseAssetName returns the binary published for a platform
+func releaseAssetName(goos, goarch string) string {
+	return fmt.Sprintf("%s-%s-%s", releaseAssetPrefix, goos, goarch)
+}
+
+// releaseClient is used for the release API and downloads
+var releaseClient = &http.Client{Timeout: 2 * time.Minute}
+
+// httpGet fetches a URL, sending GITHUB_TOKEN when set to avoid the anonymous rate limit
+func httpGet(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "ai-menu/"+version)
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := releaseClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp, nil
+}
+
+// fetchRelease returns the latest release, or the release of a tag
+func fetchRelease(apiURL, tag string) (Release, error) {
+	url := strings.TrimSuffix(apiURL, "/") + "/releases/latest"
+	if tag != "" {
+		url = strings.TrimSuffix(apiURL, "/") + "/releases/tags/" + tag
+	}
+
+	resp, err := httpGet(url)
+	if err != nil {
+		return Release{}, err
+	}
+	defer resp.Body.Close()
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return Release{}, fmt.Errorf("could not read release: %w", err)
+	}
+	if release.TagName == "" {
+		return Release{}, fmt.Errorf("release has no tag")
+	}
+	return release, nil
+}
+
+// parseVersion splits a version such as v1.2.3 or 1.2.3-rc1 into its numbers
+func parseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	numbers := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, len(numbers) > 0
+}
+
+// compareVersions returns -1, 0 or 1 as a is older than, equal to or newer than b. The
+// second result is false when either is not a release version, such as a dev build.
+func compareVersions(a, b string) (int, bool) {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := 0; i < len(va) || i < len(vb); i++ {
+		var x, y int
+		if i < len(va) {
+			x = va[i]
+		}
+		if i < len(vb) {
+			y = vb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+// expectedChecksum downloads a .sha256 file, as written by sha256sum, and returns the hash
+// listed for the asset
+func expectedChecksum(url, assetName string) (string, error) {
+	resp, err := httpGet(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		// A bare hash, or "hash  name" where the name may carry a * for binary mode
+		if len(fields) == 1 || strings.TrimPrefix(fields[1], "*") == assetName {
+			if _, err := hex.DecodeString(fields[0]); err != nil || len(fields[0]) != sha256.Size*2 {
+				return "", fmt.Errorf("malformed checksum in %s", url)
+			}
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no checksum for %s in %s", assetName, url)
+}
+
+// replaceExecutable downloads the asset next to the executable, verifies it against the
+// checksum and renames it over the executable, so the binary is either fully replaced or
+// left untouched
+func replaceExecutable(exePath string, asset ReleaseAsset, checksum string) error {
+	resp, err := httpGet(asset.URL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// The temporary file must be on the same file system for the rename to be atomic
+	tmp, err := os.CreateTemp(filepath.Dir(exePath), "."+filepath.Base(exePath)+".update-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), resp.Body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("download failed: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != checksum {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", asset.Name, checksum, actual)
+	}
+
+	mode := os.FileMode(0755)
+	if stat, err := os.Stat(exePath); err == nil {
+		mode = stat.Mode().Perm() | 0111
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), exePath)
+}
+
+// runSelfUpdate replaces the running binary with the release for this platform
+func runSelfUpdate(args []string, in io.Reader, out io.Writer) int {
+	fs := flag.NewFlagSet("self-update", flag.ContinueOnError)
+	check := fs.Bool("check", false, "only report whether an update is available")
+	tag := fs.String("version", "", "install this release tag instead of the latest, e.g. v0.3.0")
+	force := fs.Bool("force", false, "install even when the release is not newer, or the running binary is a dev build")
+	yes := fs.Bool("yes", false, "update without asking for confirmation")
+	if code, stop := parseCommandFlags(fs, args); stop {
+		return code
+	}
+
+	apiURL := releaseAPI
+	if override := os.Getenv("AI_MENU_RELEASE_API"); override != "" {
+		apiURL = override
+	}
+
+	current := currentVersion().Version
+	release, err := fetchRelease(apiURL, *tag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: could not check for updates: %v\n", err)
+		return exitFailed
+	}
+
+	order, comparable := compareVersions(current, release.TagName)
+	switch {
+	case !comparable && !*force:
+		fmt.Fprintf(out, "Running %s; the latest release is %s. Use --force to replace this build.\n", current, release.TagName)
+		return exitOK
+	case comparable && order >= 0 && !*force:
+		fmt.Fprintf(out, "✓ ai-menu %s is up to date (latest release: %s)\n", current, release.TagName)
+		return exitOK
+	}
+
+	if *check {
+		fmt.Fprintf(out, "Update available: %s → %s\n", current, release.TagName)
+		return exitOK
+	}
+
+	assetName := releaseAssetName(runtime.GOOS, runtime.GOARCH)
+	asset, ok := release.asset(assetName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "ai-menu: release %s has no binary for %s/%s (%s)\n", release.TagName, runtime.GOOS, runtime.GOARCH, assetName)
+		return exitFailed
+	}
+	checksumAsset, ok := release.asset(assetName + ".sha256")
+	if !ok {
+		fmt.Fprintf(os.Stderr, "ai-menu: release %s has no checksum for %s; not updating\n", release.TagName, assetName)
+		return exitFailed
+	}
+
+	exePath, err := os.Executable()
+	if err == nil {
+		exePath, err = filepath.EvalSymlinks(exePath)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: could not locate the running binary: %v\n", err)
+		return exitFailed
+	}
+
+	if !*yes && !confirm(in, out, fmt.Sprintf("Replace %s (%s) with %s?", exePath, current, release.TagName)) {
+		fmt.Fprintln(out, "Update cancelled.")
+		return exitNotConfirmed
+	}
+
+	checksum, err := expectedChecksum(checksumAsset.URL, assetName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: could not read the checksum: %v\n", err)
+		return exitFailed
+	}
+	if err := replaceExecutable(exePath, asset, checksum); err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: update failed, %s was not changed: %v\n", exePath, err)
+		return exitFailed
+	}
+
+	fmt.Fprintf(out, "✓ Updated %s from %s to %s\n", exePath, current, release.TagName)
+	return exitOK
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// releaseServer is a local stand-in for the GitHub release API and its downloads
+type releaseServer struct {
+	*httptest.Server
+	releases map[string]Release // by tag; "latest" is served for /releases/latest
+	files    map[string]string  // download path to content
+}
+
+func newReleaseServer(t *testing.T) *releaseServer {
+	t.Helper()
+	s := &releaseServer{releases: map[string]Release{}, files: map[string]string{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tag := ""
+		switch {
+		case r.URL.Path == "/releases/latest":
+			tag = "latest"
+		case strings.HasPrefix(r.URL.Path, "/releases/tags/"):
+			tag = strings.TrimPrefix(r.URL.Path, "/releases/tags/")
+		default:
+			content, ok := s.files[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, content)
+			return
+		}
+		release, ok := s.releases[tag]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(release)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// addRelease publishes a release whose binary has the given content; the checksum file is
+// only attached when checksum is not empty
+func (s *releaseServer) addRelease(tag, content, checksum string, latest bool) Release {
+	name := releaseAssetName(runtime.GOOS, runtime.GOARCH)
+	release := Release{TagName: tag}
+	if content != "" {
+		path := "/download/" + tag + "/" + name
+		s.files[path] = content
+		release.Assets = append(release.Assets, ReleaseAsset{Name: name, URL: s.URL + path})
+	}
+	if checksum != "" {
+		path := "/download/" + tag + "/" + name + ".sha256"
+		s.files[path] = checksum
+		release.Assets = append(release.Assets, ReleaseAsset{Name: name + ".sha256", URL: s.URL + path})
+	}
+	s.releases[tag] = release
+	if latest {
+		s.releases["latest"] = release
+	}
+	return release
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestFetchRelease(t *testing.T) {
+	server := newReleaseServer(t)
+	server.addRelease("v1.0.0", "old", "", false)
+	server.addRelease("v1.1.0", "new", "", true)
+
+	tests := []struct {
+		tag     string
+		want    string
+		wantErr bool
+	}{
+		{"", "v1.1.0", false},
+		{"v1.0.0", "v1.0.0", false},
+		{"v9.9.9", "", true},
+	}
+	for _, tt := range tests {
+		release, err := fetchRelease(server.URL, tt.tag)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("fetchRelease(%q) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+			continue
+		}
+		if release.TagName != tt.want {
+			t.Errorf("fetchRelease(%q) = %q, want %q", tt.tag, release.TagName, tt.want)
+		}
+	}
+}
+
+func TestExpectedChecksum(t *testing.T) {
+	server := newReleaseServer(t)
+	hash := sha256Hex("binary")
+	name := "aimenu-linux-amd64"
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"bare hash", hash + "\n", hash, false},
+		{"text mode", hash + "  " + name + "\n", hash, false},
+		{"binary mode", hash + " *" + name + "\n", hash, false},
+		{"upper case", strings.ToUpper(hash) + "  " + name + "\n", hash, false},
+		{"other files listed first", sha256Hex("x") + "  aimenu-darwin-arm64\n" + hash + "  " + name + "\n", hash, false},
+		{"not listed", hash + "  aimenu-darwin-arm64\n", "", true},
+		{"malformed", "abc  " + name + "\n", "", true},
+	}
+	for i, tt := range tests {
+		path := fmt.Sprintf("/sums/%d", i)
+		server.files[path] = tt.content
+		got, err := expectedChecksum(server.URL+path, name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	server := newReleaseServer(t)
+	release := server.addRelease("v1.1.0", "new binary", "", true)
+	asset := release.Assets[0]
+
+	tests := []struct {
+		name     string
+		checksum string
+		want     string
+		wantErr  bool
+	}{
+		{"verified", sha256Hex("new binary"), "new binary", false},
+		{"checksum mismatch", sha256Hex("something else"), "old binary", true},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		exe := filepath.Join(dir, "ai-menu")
+		if err := os.WriteFile(exe, []byte("old binary"), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		err := replaceExecutable(exe, asset, tt.checksum)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		content, _ := os.ReadFile(exe)
+		if string(content) != tt.want {
+			t.Errorf("%s: executable contains %q, want %q", tt.name, content, tt.want)
+		}
+		if stat, err := os.Stat(exe); err != nil || stat.Mode().Perm()&0111 == 0 {
+			t.Errorf("%s: executable lost its mode: %v", tt.name, err)
+		}
+		// No temporary download is left behind
+		if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+			t.Errorf("%s: %d files in the directory, want 1", tt.name, len(entries))
+		}
+	}
+}
+
+func TestRunSelfUpdateMissingAssets(t *testing.T) {
+	server := newReleaseServer(t)
+	server.addRelease("v2.0.0", "", sha256Hex("x"), false)
+	server.addRelease("v2.1.0", "binary", "", false)
+	t.Setenv("AI_MENU_RELEASE_API", server.URL)
+
+	// Both checks happen before the running binary is located, so it is never touched
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"v2.0.0", "has no binary"},
+		{"v2.1.0", "has no checksum"},
+	}
+	for _, tt := range tests {
+		stderr := captureStderr(t, func() {
+			var out bytes.Buffer
+			if code := runSelfUpdate([]string{"--version", tt.tag, "--force", "--yes"}, strings.NewReader(""), &out); code != exitFailed {
+				t.Errorf("%s: exit code %d, want %d", tt.tag, code, exitFailed)
+			}
+		})
+		if !strings.Contains(stderr, tt.want) {
+			t.Errorf("%s: stderr %q does not mention %q", tt.tag, stderr, tt.want)
+		}
+	}
+}
+
+func TestRunSelfUpdateCheck(t *testing.T) {
+	server := newReleaseServer(t)
+	server.addRelease("v1.1.0", "binary", sha256Hex("binary"), true)
+	t.Setenv("AI_MENU_RELEASE_API", server.URL)
+
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"v1.0.0", "Update available: v1.0.0 → v1.1.0"},
+		{"v1.1.0", "is up to date"},
+		{"v1.2.0", "is up to date"},
+		{"dev", "Use --force"},
+	}
+	defer func(saved string) { version = saved }(version)
+	for _, tt := range tests {
+		version = tt.version
+		var out bytes.Buffer
+		if code := runSelfUpdate([]string{"--check"}, strings.NewReader(""), &out); code != exitOK {
+			t.Errorf("%s: exit code %d, want %d", tt.version, code, exitOK)
+		}
+		if !strings.Contains(out.String(), tt.want) {
+			t.Errorf("%s: output %q does not contain %q", tt.version, out.String(), tt.want)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b           string
+		want           int
+		wantComparable bool
+	}{
+		{"v1.2.3", "v1.2.3", 0, true},
+		{"v1.2.3", "v1.10.0", -1, true},
+		{"1.3", "v1.2.9", 1, true},
+		{"v1.2", "v1.2.0", 0, true},
+		{"v1.2.3-rc1", "v1.2.3", 0, true},
+		// git describe builds count as the tag they were built from
+		{"v1.2.3-4-gabcdef0", "v1.2.3", 0, true},
+		{"v1.2.3-4-gabcdef0-dirty", "v1.2.4", -1, true},
+		// dev builds and bare commits cannot be compared
+		{"dev", "v1.2.3", 0, false},
+		{"abcdef0", "v1.2.3", 0, false},
+		{"v1.2.3", "", 0, false},
+	}
+	for _, tt := range tests {
+		got, comparable := compareVersions(tt.a, tt.b)
+		if got != tt.want || comparable != tt.wantComparable {
+			t.Errorf("compareVersions(%q, %q) = %d, %v, want %d, %v", tt.a, tt.b, got, comparable, tt.want, tt.wantComparable)
+		}
+	}
+}
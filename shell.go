@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Supported shells for alias integration
+const (
+	shellBash = "bash"
+	shellZsh  = "zsh"
+	shellFish = "fish"
+)
+
+// supportedShells lists the shells ai-menu can write aliases for, in display order
+var supportedShells = []string{shellBash, shellZsh, shellFish}
+
+// detectLoginShell returns the user's login shell from $SHELL, or "" if it is not supported
+func detectLoginShell() string {
+	name := filepath.Base(os.Getenv("SHELL"))
+	for _, shell := range supportedShells {
+		if shell == name {
+			return shell
+		}
+	}
+	return ""
+}
+
+// detectInstalledShells returns the supported shells found on PATH
+func detectInstalledShells() map[string]bool {
+	installed := make(map[string]bool)
+	for _, shell := range supportedShells {
+		if _, err := exec.LookPath(shell); err == nil {
+			installed[shell] = true
+		}
+	}
+	return installed
+}
+
+// defaultShellTargets returns the shells to configure when the user has not chosen any
+func defaultShellTargets() []string {
+	if login := detectLoginShell(); login != "" {
+		return []string{login}
+	}
+	return []string{shellZsh}
+}
+
+// shellRCPath returns the startup file ai-menu edits for a shell
+func shellRCPath(shell string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	switch shell {
+	case shellBash:
+		return filepath.Join(homeDir, ".bashrc"), nil
+	case shellZsh:
+		// zsh reads .zshrc from ZDOTDIR when it is set
+		if zdotdir := os.Getenv("ZDOTDIR"); zdotdir != "" {
+			return filepath.Join(zdotdir, ".zshrc"), nil
+		}
+		return filepath.Join(homeDir, ".zshrc"), nil
+	case shellFish:
+		// fish sources every file in conf.d automatically
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			configHome = filepath.Join(homeDir, ".config")
+		}
+		return filepath.Join(configHome, "fish", "conf.d", "ai-menu.fish"), nil
+	}
+	return "", fmt.Errorf("unsupported shell %q", shell)
+}
+
+// displayPath shortens a path under the home directory to ~/...
+func displayPath(path string) string {
+	if homeDir, err := os.UserHomeDir(); err == nil && strings.HasPrefix(path, homeDir+"/") {
+		return "~" + strings.TrimPrefix(path, homeDir)
+	}
+	return path
+}
+
+// formatAlias returns an alias definition in the given shell's syntax
+func formatAlias(shell, name, command string) string {
+	if shell == shellFish {
+		// fish functions forward their arguments explicitly
+		return fmt.Sprintf("function %s --description 'ai-menu alias'\n    %s $argv\nend", name, command)
+	}
+	return fmt.Sprintf("alias %s='%s'", name, command)
+}
+
+// formatWrapperFunction returns a bash/zsh function that runs the command with all arguments.
+// An alias of the same name left from a previous run would take precedence, so it is removed first.
+func formatWrapperFunction(name, command string) string {
+	return fmt.Sprintf("unalias %s 2>/dev/null; function %s { %s \"$@\"; }", name, name, command)
+}
+
+// formatPathPrepend returns the line that puts a directory at the front of PATH
+func formatPathPrepend(shell, dir string) string {
+	if shell == shellFish {
+		return fmt.Sprintf("fish_add_path --global --prepend %q", dir)
+	}
+	// Guard against duplicate entries when the rc file is sourced repeatedly
+	return fmt.Sprintf("case \":$PATH:\" in *\":%s:\"*) ;; *) export PATH=\"%s:$PATH\" ;; esac", dir, dir)
+}
+
+// sourceHint returns the command that reloads the shell configuration
+func sourceHint(shell string) string {
+	rcPath, err := shellRCPath(shell)
+	if err != nil {
+		return ""
+	}
+	return "source " + displayPath(rcPath)
+}
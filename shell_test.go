@@ -0,0 +1,63 @@
+package main
+
+import "testing"
+
+func TestFormatAlias(t *testing.T) {
+	const command = "pixi run --manifest-path /work/ai-dev-pixi codex"
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{shellBash, "alias codex='" + command + "'"},
+		{shellZsh, "alias codex='" + command + "'"},
+		{shellFish, "function codex --description 'ai-menu alias'\n    " + command + " $argv\nend"},
+	}
+	for _, tt := range tests {
+		if got := formatAlias(tt.shell, "codex", command); got != tt.want {
+			t.Errorf("formatAlias(%s) = %q, want %q", tt.shell, got, tt.want)
+		}
+	}
+}
+
+func TestFormatWrapperFunction(t *testing.T) {
+	tests := []struct {
+		name, command string
+		want          string
+	}{
+		{"codex", "pixi run codex", `unalias codex 2>/dev/null; function codex { pixi run codex "$@"; }`},
+		{"bat", "batcat", `unalias bat 2>/dev/null; function bat { batcat "$@"; }`},
+	}
+	for _, tt := range tests {
+		if got := formatWrapperFunction(tt.name, tt.command); got != tt.want {
+			t.Errorf("formatWrapperFunction(%q, %q) = %q, want %q", tt.name, tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestShellRCPath(t *testing.T) {
+	tests := []struct {
+		shell, zdotdir, configHome string
+		want                       string
+		wantErr                    bool
+	}{
+		{shellBash, "", "", "/home/u/.bashrc", false},
+		{shellBash, "/home/u/.zsh", "", "/home/u/.bashrc", false},
+		{shellZsh, "", "", "/home/u/.zshrc", false},
+		// zsh reads its startup files from ZDOTDIR
+		{shellZsh, "/home/u/.zsh", "", "/home/u/.zsh/.zshrc", false},
+		{shellFish, "", "", "/home/u/.config/fish/conf.d/ai-menu.fish", false},
+		{shellFish, "", "/xdg", "/xdg/fish/conf.d/ai-menu.fish", false},
+		{"tcsh", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("HOME", "/home/u")
+		t.Setenv("ZDOTDIR", tt.zdotdir)
+		t.Setenv("XDG_CONFIG_HOME", tt.configHome)
+
+		got, err := shellRCPath(tt.shell)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("shellRCPath(%s) with ZDOTDIR=%q XDG_CONFIG_HOME=%q = %q, %v, want %q, wantErr %v",
+				tt.shell, tt.zdotdir, tt.configHome, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Shell integration modes
+const (
+	modeAliases = "aliases"
+	modeShims   = "shims"
+)
+
+// shimMarker identifies scripts generated by ai-menu so stale ones can be cleaned up safely
+const shimMarker = "# Generated by ai-menu"
+
+// shimBinDir returns the directory holding the wrapper scripts of an environment
+func shimBinDir(envPath string) string {
+	return filepath.Join(envPath, "bin")
+}
+
+// renderShim returns a POSIX sh wrapper that runs the command with all arguments
+func renderShim(envName, command string) string {
+	return fmt.Sprintf("#!/bin/sh\n%s for environment %s\nexec %s \"$@\"\n", shimMarker, envName, command)
+}
+
+// WriteShims regenerates the executable wrapper scripts for every command of the environment,
+// removing shims for tools that are no longer recorded
+func WriteShims(env EnvironmentRecord, globals map[string]string, names AliasNames) (string, int, error) {
+	binDir := shimBinDir(env.Path)
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return binDir, 0, err
+	}
+
+	commands := environmentCommands(env, globals, names)
+	wanted := make(map[string]bool, len(commands))
+	for _, command := range commands {
+		wanted[command.Name] = true
+		shimPath := filepath.Join(binDir, command.Name)
+		if err := os.WriteFile(shimPath, []byte(renderShim(env.Name, command.Command)), 0755); err != nil {
+			return binDir, 0, err
+		}
+		// WriteFile keeps the mode of an existing file, so make sure it is executable
+		if err := os.Chmod(shimPath, 0755); err != nil {
+			return binDir, 0, err
+		}
+	}
+
+	// Remove shims generated earlier for tools that are gone
+	entries, err := os.ReadDir(binDir)
+	if err != nil {
+		return binDir, len(commands), err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || wanted[entry.Name()] {
+			continue
+		}
+		path := filepath.Join(binDir, entry.Name())
+		if content, err := os.ReadFile(path); err == nil && bytes.Contains(content, []byte(shimMarker)) {
+			os.Remove(path)
+		}
+	}
+
+	return binDir, len(commands), nil
+}
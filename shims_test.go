@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestWriteShims(t *testing.T) {
+	envPath := t.TempDir()
+	binDir := shimBinDir(envPath)
+	if err := os.MkdirAll(filepath.Join(binDir, "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := map[string]struct {
+		content string
+		mode    os.FileMode
+	}{
+		"gemini": {renderShim("team", "pixi run gemini"), 0755}, // a tool that was uninstalled
+		"codex":  {renderShim("team", "old command"), 0644},     // rewritten and made executable
+		"mine":   {"#!/bin/sh\necho mine\n", 0755},              // not generated by ai-menu
+	}
+	for name, file := range existing {
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte(file.content), file.mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	env := EnvironmentRecord{Name: "team", Path: envPath, Tools: []ToolRecord{
+		{Name: "@openai/codex", Category: categoryCLI, Alias: "codex", Command: "codex"},
+		{Name: "jq", Category: categorySpecial, Alias: "jq", Command: "jq"},
+	}}
+	tests := []struct {
+		name      string
+		tools     []ToolRecord
+		wantFiles []string
+	}{
+		{"stale shims removed", env.Tools, []string{"codex", "jq", "lib", "mine", "npm", "npx"}},
+		// Dropping the only CLI tool also drops the npm and npx shims
+		{"tool removed later", env.Tools[1:], []string{"jq", "lib", "mine"}},
+	}
+	for _, tt := range tests {
+		env.Tools = tt.tools
+		dir, count, err := WriteShims(env, nil, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if dir != binDir || count != len(tt.wantFiles)-2 {
+			t.Errorf("%s: WriteShims = %s, %d, want %s, %d", tt.name, dir, count, binDir, len(tt.wantFiles)-2)
+		}
+
+		entries, err := os.ReadDir(binDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := []string{}
+		for _, entry := range entries {
+			files = append(files, entry.Name())
+		}
+		sort.Strings(files)
+		if !reflect.DeepEqual(files, tt.wantFiles) {
+			t.Errorf("%s: bin contains %v, want %v", tt.name, files, tt.wantFiles)
+		}
+		for _, tool := range tt.tools {
+			if info, err := os.Stat(filepath.Join(binDir, tool.Alias)); err != nil || info.Mode().Perm() != 0755 {
+				t.Errorf("%s: %s shim is not executable: %v", tt.name, tool.Alias, err)
+			}
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(binDir, "jq"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "exec pixi run --manifest-path "+envPath+" jq \"$@\"") {
+		t.Errorf("jq shim = %q", content)
+	}
+}
@@ -28,6 +28,10 @@ var (
 	uncheckedStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#666666"))
 
+	disabledItemStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#444444")).
+				Strikethrough(true)
+
 	helpStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#626262")).
 			Padding(1, 0)
@@ -40,4 +44,44 @@ var (
 	spinnerStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#00FFFF")).
 			Bold(true)
+
+	detailStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#444444")).
+			Padding(0, 1)
+)
+
+// Themes selectable with ui.theme in config.toml
+const (
+	themeDefault = "default"
+	themeLight   = "light"
+	themeMono    = "mono"
 )
+
+var themeNames = []string{themeDefault, themeLight, themeMono}
+
+// applyTheme recolours the styles. The default colours assume a dark terminal; light
+// darkens them for light backgrounds and mono drops colour altogether.
+func applyTheme(name string) {
+	switch name {
+	case themeLight:
+		titleStyle = titleStyle.Foreground(lipgloss.Color("#5A3FC0")).BorderForeground(lipgloss.Color("#5A3FC0"))
+		selectedItemStyle = selectedItemStyle.Foreground(lipgloss.Color("#5A3FC0"))
+		normalItemStyle = normalItemStyle.Foreground(lipgloss.Color("#1A1A1A"))
+		checkedStyle = checkedStyle.Foreground(lipgloss.Color("#008700"))
+		uncheckedStyle = uncheckedStyle.Foreground(lipgloss.Color("#8A8A8A"))
+		disabledItemStyle = disabledItemStyle.Foreground(lipgloss.Color("#B2B2B2"))
+		helpStyle = helpStyle.Foreground(lipgloss.Color("#6C6C6C"))
+		summaryStyle = summaryStyle.Foreground(lipgloss.Color("#AF5F00"))
+		spinnerStyle = spinnerStyle.Foreground(lipgloss.Color("#008787"))
+		detailStyle = detailStyle.BorderForeground(lipgloss.Color("#B2B2B2"))
+	case themeMono:
+		for _, style := range []*lipgloss.Style{&titleStyle, &selectedItemStyle, &normalItemStyle, &checkedStyle,
+			&uncheckedStyle, &disabledItemStyle, &helpStyle, &summaryStyle, &spinnerStyle, &detailStyle} {
+			*style = style.UnsetForeground().UnsetBorderForeground()
+		}
+		selectedItemStyle = selectedItemStyle.Underline(true)
+		uncheckedStyle = uncheckedStyle.Faint(true)
+		helpStyle = helpStyle.Faint(true)
+	}
+}
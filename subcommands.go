@@ -0,0 +1,439 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// writeJSON prints a value as indented JSON
+func writeJSON(out io.Writer, v interface{}) {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// parseCommandFlags parses a subcommand's flags, returning the exit code to stop with, if any
+func parseCommandFlags(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK, true
+		}
+		return exitUsage, true
+	}
+	return exitOK, false
+}
+
+// resolveEnvironment returns the named environment, or the active one when no name is given
+func resolveEnvironment(state *State, name string) (*EnvironmentRecord, error) {
+	if name == "" {
+		name = state.Active
+	}
+	if name == "" {
+		return nil, fmt.Errorf("no active environment; pass --env")
+	}
+	env := state.Environment(name)
+	if env == nil {
+		return nil, fmt.Errorf("unknown environment %q", name)
+	}
+	return env, nil
+}
+
+// catalogCategories pairs each catalog with the category name used on the command line
+var catalogCategories = []struct {
+	name    string
+	catalog []CatalogEntry
+}{
+	{categoryCLI, cliToolCatalog},
+	{categoryVSCode, vscodeExtensionCatalog},
+	{categorySpecial, specialToolCatalog},
+	{categoryEnhancer, cliEnhancerCatalog},
+}
+
+// catalogItemJSON is the JSON form of a catalog entry in ai-menu list
+type catalogItemJSON struct {
+	Category    string   `json:"category"`
+	Name        string   `json:"name"`
+	Package     string   `json:"package"`
+	Alias       string   `json:"alias,omitempty"`
+	Platforms   []string `json:"platforms,omitempty"`
+	Available   bool     `json:"available"`
+	Installed   bool     `json:"installed"`
+	Credentials []string `json:"credentials,omitempty"`
+}
+
+// runList prints the catalog with availability on this platform and installation state
+func runList(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	category := fs.String("category", "", "only list one category: cli, vscode, special or enhancer")
+	envName := fs.String("env", "", "environment to report installed tools for (default: the active environment)")
+	installedOnly := fs.Bool("installed", false, "only list installed tools")
+	jsonOutput := fs.Bool("json", false, "print JSON")
+	if code, stop := parseCommandFlags(fs, args); stop {
+		return code
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+	}
+	env, _ := resolveEnvironment(state, *envName)
+	cfg, _ := LoadConfig()
+
+	platform := hostPlatform()
+	items := []catalogItemJSON{}
+	for _, group := range catalogCategories {
+		if *category != "" && *category != group.name {
+			continue
+		}
+		// Disabled categories are only listed when asked for by name
+		if *category == "" && !cfg.CategoryEnabled(group.name) {
+			continue
+		}
+		for _, entry := range group.catalog {
+			item := catalogItemJSON{
+				Category:    group.name,
+				Name:        entry.Name,
+				Package:     entry.Package,
+				Platforms:   entry.Platforms,
+				Available:   entry.SupportsPlatform(platform),
+				Credentials: entry.Credentials,
+			}
+			if group.name == categoryCLI || group.name == categoryEnhancer {
+				item.Alias = getAliasName(entry.Package)
+			}
+			if env != nil {
+				_, item.Installed = env.FindTool(entry.Package)
+			}
+			if *installedOnly && !item.Installed {
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+
+	if *category != "" && len(items) == 0 && !*installedOnly {
+		fmt.Fprintf(os.Stderr, "ai-menu: unknown category %q\n", *category)
+		return exitUsage
+	}
+
+	if *jsonOutput {
+		writeJSON(out, items)
+		return exitOK
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CATEGORY\tNAME\tALIAS\tSTATUS")
+	for _, item := range items {
+		status := ""
+		switch {
+		case item.Installed:
+			status = "installed"
+		case !item.Available:
+			status = "unavailable on " + platform
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Category, item.Name, item.Alias, status)
+	}
+	w.Flush()
+	return exitOK
+}
+
+// statusJSON is the JSON form of ai-menu status
+type statusJSON struct {
+	Active       string              `json:"active,omitempty"`
+	Mode         string              `json:"mode"`
+	Shells       []string            `json:"shells"`
+	Environments []environmentStatus `json:"environments"`
+}
+
+// environmentStatus describes one environment in ai-menu status
+type environmentStatus struct {
+	EnvironmentRecord
+	Active bool `json:"active"`
+	Exists bool `json:"exists"`
+}
+
+// runStatus prints the known environments, their tools and the shell integration
+func runStatus(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	jsonOutput := fs.Bool("json", false, "print JSON")
+	if code, stop := parseCommandFlags(fs, args); stop {
+		return code
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitFailed
+	}
+
+	status := statusJSON{Active: state.Active, Mode: state.IntegrationMode(), Shells: state.Shells(), Environments: []environmentStatus{}}
+	for _, env := range state.Environments {
+		status.Environments = append(status.Environments, environmentStatus{EnvironmentRecord: env, Active: env.Name == state.Active, Exists: env.Exists()})
+	}
+
+	if *jsonOutput {
+		writeJSON(out, status)
+		return exitOK
+	}
+
+	fmt.Fprintf(out, "Shell integration: %s in %s\n", status.Mode, strings.Join(status.Shells, ", "))
+	if len(status.Environments) == 0 {
+		fmt.Fprintln(out, "No environments yet; run 'ai-menu' or 'ai-menu install' to create one.")
+		return exitOK
+	}
+	for _, env := range status.Environments {
+		marker := " "
+		if env.Active {
+			marker = "*"
+		}
+		missing := ""
+		if !env.Exists {
+			missing = " (missing)"
+		}
+		fmt.Fprintf(out, "\n%s %s  %s%s\n", marker, env.Name, env.Path, missing)
+		for _, tool := range env.Tools {
+			fmt.Fprintf(out, "    %-14s %s\n", tool.Alias, tool.Name)
+		}
+	}
+	return exitOK
+}
+
+// runDoctor prints the diagnostic checks and fails when a required check fails
+func runDoctor(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	jsonOutput := fs.Bool("json", false, "print JSON")
+	if code, stop := parseCommandFlags(fs, args); stop {
+		return code
+	}
+
+	checks := RunDoctor()
+	if *jsonOutput {
+		writeJSON(out, checks)
+	} else {
+		for _, check := range checks {
+			fmt.Fprintln(out, formatCheck(check))
+		}
+	}
+
+	if !doctorPassed(checks) {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// runUninstall removes tools from an environment and regenerates its aliases and shims
+func runUninstall(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("uninstall", flag.ContinueOnError)
+	envName := fs.String("env", "", "environment to remove the tools from (default: the active environment)")
+	report := addReportFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: ai-menu uninstall [flags] <tool>...")
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	if code, stop := parseCommandFlags(fs, args); stop {
+		return code
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	if err := report.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitUsage
+	}
+
+	log := report.log(out)
+	progress := func(msg string) { fmt.Fprintln(log, msg) }
+
+	state, err := LoadState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitFailed
+	}
+	env, err := resolveEnvironment(state, *envName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitUsage
+	}
+
+	// Resolve every argument before removing anything, so a typo leaves the environment untouched
+	removals := []func() InstallResult{}
+	for _, name := range fs.Args() {
+		// VS Code extensions are not recorded per environment
+		if items, err := resolveCatalogItems(vscodeExtensionCatalog, []string{name}); err == nil {
+			entry, _ := lookupCatalogEntry(items[0])
+			removals = append(removals, func() InstallResult { return UninstallVSCodeExtension(entry.Package, progress) })
+			continue
+		}
+
+		tool, found := env.FindTool(name)
+		if !found {
+			// Accept any catalog name for a recorded tool, e.g. "Codex by OpenAI"
+			for _, group := range catalogCategories {
+				if items, err := resolveCatalogItems(group.catalog, []string{name}); err == nil {
+					entry, _ := lookupCatalogEntry(items[0])
+					tool, found = env.FindTool(entry.Package)
+					break
+				}
+			}
+		}
+		if !found {
+			fmt.Fprintf(os.Stderr, "ai-menu: %s is not installed in environment %s\n", name, env.Name)
+			return exitUsage
+		}
+		removals = append(removals, func() InstallResult { return UninstallTool(env, tool, progress) })
+	}
+
+	started := time.Now()
+	results := []InstallResult{}
+	for _, remove := range removals {
+		results = append(results, remove())
+	}
+
+	// Drop the aliases and shims of removed tools
+	if state.Active == env.Name {
+		if err := ActivateEnvironment(state, env.Name, progress); err != nil {
+			progress(fmt.Sprintf("⚠️  Could not update aliases: %v", err))
+		}
+	} else if err := state.Save(); err != nil {
+		progress(fmt.Sprintf("⚠️  Could not save state: %v", err))
+	}
+
+	code := summarizeResults(results, "removed", log)
+	report.write(out, NewReport(env.Name, env.Path, started, results))
+	return code
+}
+
+// runUpgrade updates the environment's core dependencies and reinstalls its recorded tools at their latest versions
+func runUpgrade(args []string, in io.Reader, out io.Writer) int {
+	fs := flag.NewFlagSet("upgrade", flag.ContinueOnError)
+	envName := fs.String("env", "", "environment to upgrade (default: the active environment)")
+	yes := fs.Bool("yes", false, "upgrade without asking for confirmation")
+	report := addReportFlags(fs)
+	if code, stop := parseCommandFlags(fs, args); stop {
+		return code
+	}
+	if err := report.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitUsage
+	}
+	log := report.log(out)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+	}
+	state, err := LoadState()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitFailed
+	}
+	env, err := resolveEnvironment(state, *envName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+		return exitUsage
+	}
+
+	plan := upgradePlan(*env, cfg, state)
+	printPlan(log, plan)
+	if !preflightPassed(plan, log) {
+		return exitPreflight
+	}
+	if !*yes && !confirm(in, log, "Upgrade these tools?") {
+		fmt.Fprintln(log, "Upgrade cancelled")
+		return exitNotConfirmed
+	}
+
+	started := time.Now()
+
+	// Refresh the locked core dependencies before reinstalling the tools on top of them
+	fmt.Fprintln(log, "Updating pixi dependencies...")
+	cmd := exec.Command("pixi", "update", "--manifest-path", env.Path)
+	cmd.Stdout = log
+	cmd.Stderr = log
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(log, "⚠️  pixi update failed: %v\n", err)
+	}
+
+	results, code := runPlan(plan, log)
+	report.write(out, NewReport(env.Name, env.Path, started, results))
+	return code
+}
+
+// upgradePlan builds an installation plan that upgrades the tools recorded in the environment,
+// each in the pixi environment it was installed into
+func upgradePlan(env EnvironmentRecord, cfg Config, state *State) InstallPlan {
+	plan := InstallPlan{
+		InstallPath:  filepath.Dir(env.Path),
+		EnvName:      env.Name,
+		Core:         cfg.Core,
+		ShellTargets: state.Shells(),
+		Mode:         state.IntegrationMode(),
+		AliasNames:   state.AliasNames,
+		Registries:   cfg.Registries,
+		Concurrency:  cfg.UI.Concurrency,
+		ToolEnvs:     map[string]string{},
+		Upgrade:      true,
+	}
+
+	for _, tool := range env.Tools {
+		entry, ok := lookupCatalogPackage(tool.Name)
+		if !ok {
+			continue
+		}
+		plan.ToolEnvs[tool.Name] = tool.PixiEnv
+		switch tool.Category {
+		case categoryCLI:
+			plan.CLITools = append(plan.CLITools, entry.Name)
+		case categoryEnhancer:
+			plan.CLIEnhancers = append(plan.CLIEnhancers, entry.Name)
+		case categorySpecial:
+			plan.SpecialTools = append(plan.SpecialTools, entry.Name)
+		}
+	}
+	return plan
+}
+
+// runProfiles prints the built-in presets and saved profiles
+func runProfiles(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet("profiles", flag.ContinueOnError)
+	jsonOutput := fs.Bool("json", false, "print JSON")
+	if code, stop := parseCommandFlags(fs, args); stop {
+		return code
+	}
+
+	profiles, err := LoadProfiles()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ai-menu: %v\n", err)
+	}
+
+	if *jsonOutput {
+		writeJSON(out, profiles)
+		return exitOK
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tITEMS\tPATH\tDESCRIPTION")
+	for _, profile := range profiles {
+		path := ""
+		if profile.InstallPath != "" {
+			path = envDirFor(profile.InstallPath, profile.EnvName)
+		}
+		description := profile.Description
+		if profile.Builtin {
+			description += " (built-in)"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", profile.Name, profile.itemCount(), path, description)
+	}
+	w.Flush()
+	return exitOK
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunUninstallValidatesFirst(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	state := &State{Environments: []EnvironmentRecord{{
+		Name:  "team",
+		Path:  filepath.Join(dir, "team"),
+		Tools: []ToolRecord{{Name: "@openai/codex", Category: categoryCLI, Alias: "codex", Command: "codex"}},
+	}}}
+	if err := state.Save(); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := statePath()
+	before, _ := os.ReadFile(path)
+
+	// The unknown name is rejected before codex is removed
+	var out bytes.Buffer
+	captureStderr(t, func() {
+		if code := runUninstall([]string{"--env", "team", "codex", "not-a-tool"}, &out); code != exitUsage {
+			t.Errorf("exit code %d, want %d", code, exitUsage)
+		}
+	})
+	after, _ := os.ReadFile(path)
+	if !bytes.Equal(before, after) {
+		t.Errorf("state changed:\n%s\nwant:\n%s", after, before)
+	}
+	if out.Len() > 0 {
+		t.Errorf("uninstall printed %q before failing", out.String())
+	}
+}
+
+// captureStderr returns what fn writes to os.Stderr
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = saved }()
+
+	done := make(chan string)
+	go func() {
+		var b bytes.Buffer
+		b.ReadFrom(r)
+		done <- b.String()
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// npmPackageName strips the version or dist-tag from an npm package spec such as claude-flow@alpha
+func npmPackageName(spec string) string {
+	if at := strings.LastIndex(spec, "@"); at > 0 {
+		return spec[:at]
+	}
+	return spec
+}
+
+// uninstallCommand returns the command that removes a tool installed in the environment
+func uninstallCommand(envDir string, tool ToolRecord) (*exec.Cmd, error) {
+	args := []string{"run", "--manifest-path", envDir}
+	if tool.PixiEnv != "" {
+		args = append(args, "-e", tool.PixiEnv)
+	}
+
+	if tool.Name == "modal" {
+		return exec.Command("pixi", append(args, "uv", "pip", "uninstall", "modal")...), nil
+	}
+
+	switch getInstallBackend(tool.Name) {
+	case backendScript:
+		return nil, fmt.Errorf("%s was installed by its vendor script outside pixi; remove it with the vendor's instructions", tool.Name)
+	case backendUv:
+		cmd := exec.Command("pixi", append(args, "uv", "tool", "uninstall", tool.Name)...)
+		if tool.PixiEnv != "" {
+			cmd.Env = isolatedUvEnv(envDir, tool.PixiEnv)
+		}
+		return cmd, nil
+	}
+	return exec.Command("pixi", append(args, "npm", "uninstall", "-g", npmPackageName(tool.Name))...), nil
+}
+
+// UninstallTool removes a tool from the environment and forgets its record; the caller
+// regenerates aliases and shims afterwards
+func UninstallTool(env *EnvironmentRecord, tool ToolRecord, progress ProgressCallback) InstallResult {
+	progress(fmt.Sprintf("Removing %s...", tool.Name))
+	start := time.Now()
+
+	var err error
+	if tool.PixiEnv != "" {
+		// Isolated tools own their pixi environment, so removing it removes the tool
+		err = RemoveIsolatedTool(env.Path, tool.PixiEnv, progress)
+	} else {
+		var cmd *exec.Cmd
+		cmd, err = uninstallCommand(env.Path, tool)
+		if err == nil {
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+			if runErr := cmd.Run(); runErr != nil {
+				err = fmt.Errorf("%v: %s", runErr, strings.TrimSpace(stderr.String()))
+			}
+		}
+	}
+
+	var msg string
+	if err == nil {
+		env.RemoveTool(tool.Alias)
+		msg = fmt.Sprintf("✓ %s removed", tool.Name)
+	} else {
+		msg = fmt.Sprintf("✗ Failed to remove %s: %v", tool.Name, err)
+	}
+	progress(msg)
+
+	backend := getInstallBackend(tool.Name)
+	if tool.Category == categorySpecial {
+		backend = specialToolBackend(tool.Name)
+	}
+	return InstallResult{
+		Name:        tool.Name,
+		Success:     err == nil,
+		Error:       err,
+		Message:     msg,
+		Environment: tool.PixiEnv,
+		Category:    tool.Category,
+		Backend:     backend,
+		Duration:    time.Since(start),
+	}
+}
+
+// UninstallVSCodeExtension removes a VS Code extension with the code CLI
+func UninstallVSCodeExtension(extID string, progress ProgressCallback) InstallResult {
+	progress(fmt.Sprintf("Removing %s...", extID))
+	start := time.Now()
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("code", "--uninstall-extension", extID)
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var msg string
+	if err == nil {
+		msg = fmt.Sprintf("✓ %s removed", extID)
+	} else {
+		msg = fmt.Sprintf("✗ Failed to remove %s: %v", extID, err)
+	}
+	progress(msg)
+
+	return InstallResult{
+		Name:     extID,
+		Success:  err == nil,
+		Error:    err,
+		Message:  msg,
+		Category: categoryVSCode,
+		Backend:  backendVSCode,
+		Duration: time.Since(start),
+	}
+}
@@ -2,20 +2,115 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 )
 
 func (m model) renderWelcome() string {
+	// Existing environments
+	rows := make([]string, 0, len(m.environments))
+	for i, env := range m.environments {
+		cursor := " "
+		itemStyle := normalItemStyle
+		if m.cursor == i {
+			cursor = ">"
+			itemStyle = selectedItemStyle
+		}
+
+		marker := "  "
+		if env.Name == m.activeEnv {
+			marker = checkedStyle.Render("● ")
+		}
+
+		details := fmt.Sprintf("%d tool(s)", len(env.Tools))
+		if !env.Exists() {
+			details = "missing"
+		}
+
+		line := fmt.Sprintf("%s %s%s %s", cursor, marker, itemStyle.Render(env.Name), helpStyle.UnsetPadding().Render(fmt.Sprintf("%s • %s", env.Path, details)))
+		rows = append(rows, truncate(line, m.width))
+	}
+
+	var footer strings.Builder
+	if len(rows) > 0 {
+		footer.WriteString("\n")
+	}
+
+	if m.statusMessage != "" {
+		footer.WriteString(normalItemStyle.Render(m.statusMessage))
+		footer.WriteString("\n\n")
+	}
+
+	// Surface config file problems without blocking the workflow
+	if m.err != nil {
+		footer.WriteString(uncheckedStyle.Render(fmt.Sprintf("⚠️  Config: %v", m.err)))
+		footer.WriteString("\n\n")
+	}
+
+	// Warn early when the configured platforms exclude this machine
+	if err := validatePlatforms(m.config.Core.Platforms, m.platform); err != nil {
+		footer.WriteString(uncheckedStyle.Render(fmt.Sprintf("⚠️  Platforms: %v (press c to fix)", err)))
+		footer.WriteString("\n\n")
+	}
+
+	helpText := "Press enter to continue • p load a profile • c configure core dependencies • q to quit"
+	if len(m.environments) > 0 {
+		helpText = "Press enter to continue • ↑/↓ choose environment • s switch aliases to it • x remove aliases • p load a profile • c configure core dependencies • q to quit"
+	}
+	help := m.wrap(helpStyle, helpText)
+	footer.WriteString(help)
+	footer.WriteString("\n")
+
+	heading := ""
+	if len(rows) > 0 {
+		heading = summaryStyle.Render("Existing Environments:") + "\n"
+	}
+	header := m.welcomeHeader(true)
+	if len(rows) > 0 && m.height > 0 && listHeight(m.height, header+heading, footer.String()) < minListRows {
+		// Short terminals keep the room for the environments
+		header = m.welcomeHeader(false)
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	if len(rows) > 0 {
+		b.WriteString(heading)
+		for _, row := range scrollRows(rows, m.cursor, listHeight(m.height, header+heading, footer.String())) {
+			b.WriteString(row)
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(footer.String())
+
+	return b.String()
+}
+
+// welcomeHeader renders the welcome text above the environment list; without the
+// explanation it only names the core dependencies
+func (m model) welcomeHeader(explain bool) string {
 	var b strings.Builder
 
 	// Add top padding
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	if explain {
+		b.WriteString("\n")
+	}
 
 	title := titleStyle.Render("🎉 Welcome to Scott's AI World Installation Program")
 	b.WriteString(title)
 	b.WriteString("\n\n")
 
+	if !explain {
+		labels := []string{}
+		for _, dep := range m.config.Core.Dependencies() {
+			labels = append(labels, dep.Label())
+		}
+		b.WriteString(m.wrap(normalItemStyle, "Always installed: "+strings.Join(labels, ", ")))
+		b.WriteString("\n\n")
+		return b.String()
+	}
+
 	// Main welcome message
 	welcome := normalItemStyle.Render("Thank you for choosing Scott's AI World!")
 	b.WriteString(welcome)
@@ -26,382 +121,433 @@ func (m model) renderWelcome() string {
 	b.WriteString(info1)
 	b.WriteString("\n")
 
-	info2 := normalItemStyle.Render("Even if you do not select any optional tools to install,")
+	info2 := m.wrap(normalItemStyle, "Even if you do not select any optional tools to install,")
 	b.WriteString(info2)
 	b.WriteString("\n")
 
-	info3 := normalItemStyle.Render("the following will ALWAYS be installed in the ai-dev-pixi environment:")
+	info3 := m.wrap(normalItemStyle, "the following will ALWAYS be installed in the target pixi environment:")
 	b.WriteString(info3)
 	b.WriteString("\n\n")
 
 	// List of guaranteed installs
-	node := checkedStyle.Render("✓") + " " + normalItemStyle.Render("Node.js 22.*")
-	b.WriteString(node)
-	b.WriteString("\n")
-
-	python := checkedStyle.Render("✓") + " " + normalItemStyle.Render("Python 3.12.*")
-	b.WriteString(python)
+	for _, dep := range m.config.Core.Dependencies() {
+		line := checkedStyle.Render("✓") + " " + normalItemStyle.Render(dep.Label())
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
 	b.WriteString("\n")
 
-	uv := checkedStyle.Render("✓") + " " + normalItemStyle.Render("uv")
-	b.WriteString(uv)
-	b.WriteString("\n\n")
-
 	// Additional info
-	additional := helpStyle.Render("These core dependencies will be installed once and reused for")
+	additional := m.wrap(helpStyle, "These core dependencies will be installed once and reused for")
 	b.WriteString(additional)
 	b.WriteString("\n")
 
-	additional2 := helpStyle.Render("all subsequent selections, optimizing your installation time.")
+	additional2 := m.wrap(helpStyle, "all subsequent selections, optimizing your installation time.")
 	b.WriteString(additional2)
 	b.WriteString("\n\n")
 
 	// Optional selections notice
-	optional := normalItemStyle.Render("You can then select additional CLI tools, VS Code extensions,")
+	optional := m.wrap(normalItemStyle, "You can then select additional CLI tools, VS Code extensions,")
 	b.WriteString(optional)
 	b.WriteString("\n")
 
-	optional2 := normalItemStyle.Render("and special tools to customize your development environment.")
+	optional2 := m.wrap(normalItemStyle, "and special tools to customize your development environment.")
 	b.WriteString(optional2)
 	b.WriteString("\n\n")
 
-	help := helpStyle.Render("Press enter to continue • q to quit")
-	b.WriteString(help)
-	b.WriteString("\n")
-
 	return b.String()
 }
 
-func (m model) renderCLITools() string {
+func (m model) renderProfiles() string {
 	var b strings.Builder
 
 	// Add top padding
 	b.WriteString("\n")
 
-	title := titleStyle.Render("🚀 Select CLI Tools to Install")
+	title := titleStyle.Render("📋 Load a Profile")
 	b.WriteString(title)
 	b.WriteString("\n\n")
 
-	// Add "Select All" option at the top
-	cursor := " "
-	if m.cursor == 0 {
-		cursor = ">"
-	}
-
-	allSelected := len(m.selectedCLI) == len(m.cliTools)
-	checked := "[ ]"
-	checkStyle := uncheckedStyle
-	if allSelected && len(m.cliTools) > 0 {
-		checked = "[✓]"
-		checkStyle = checkedStyle
-	}
-
-	itemStyle := normalItemStyle
-	if m.cursor == 0 {
-		itemStyle = selectedItemStyle
-	}
-
-	line := fmt.Sprintf("%s %s %s", cursor, checkStyle.Render(checked), itemStyle.Render("Select All"))
-	b.WriteString(line)
+	explanation := m.wrap(helpStyle, "A profile ticks the saved tools in every screen and fills in the install path.\nSave your own from the installation summary with p.")
+	b.WriteString(explanation)
 	b.WriteString("\n\n")
 
-	// Render actual tools
-	for i, tool := range m.cliTools {
+	rows := make([]string, 0, len(m.profiles))
+	for i, profile := range m.profiles {
 		cursor := " "
-		if m.cursor == i+1 {
+		itemStyle := normalItemStyle
+		if m.cursor == i {
 			cursor = ">"
+			itemStyle = selectedItemStyle
 		}
 
-		checked := "[ ]"
-		checkStyle := uncheckedStyle
-		if m.selectedCLI[tool] {
-			checked = "[✓]"
-			checkStyle = checkedStyle
+		details := profile.Description
+		if details == "" {
+			details = fmt.Sprintf("%d item(s)", profile.itemCount())
+			if profile.InstallPath != "" {
+				details += " • " + envDirFor(profile.InstallPath, profile.EnvName)
+			}
 		}
-
-		itemStyle := normalItemStyle
-		if m.cursor == i+1 {
-			itemStyle = selectedItemStyle
+		if profile.Builtin {
+			details += " • built-in"
 		}
 
-		line := fmt.Sprintf("%s %s %s", cursor, checkStyle.Render(checked), itemStyle.Render(tool))
-		b.WriteString(line)
-		b.WriteString("\n")
+		line := fmt.Sprintf("%s %s %s", cursor, itemStyle.Render(profile.Name), helpStyle.UnsetPadding().Render(details))
+		rows = append(rows, truncate(line, m.width))
 	}
 
-	b.WriteString("\n")
-	help := helpStyle.Render("↑/k up • ↓/j down • space toggle • enter next • esc back • q quit")
-	b.WriteString(help)
-	b.WriteString("\n")
+	footer := "\n" + m.wrap(helpStyle, "↑/k up • ↓/j down • enter load • esc back • q quit") + "\n"
+
+	// Scroll long profile lists with the cursor
+	for _, row := range scrollRows(rows, m.cursor, listHeight(m.height, b.String(), footer)) {
+		b.WriteString(row)
+		b.WriteString("\n")
+	}
+	b.WriteString(footer)
 
 	return b.String()
 }
 
-func (m model) renderVSCodeExtensions() string {
+func (m model) renderCoreConfig() string {
 	var b strings.Builder
 
 	// Add top padding
 	b.WriteString("\n")
 
-	title := titleStyle.Render("🔌 Select VS Code Extensions to Install")
+	title := titleStyle.Render("⚙️  Configure Core Dependencies")
 	b.WriteString(title)
 	b.WriteString("\n\n")
 
-	// Add "Select All" option at the top
-	cursor := " "
-	if m.cursor == 0 {
-		cursor = ">"
-	}
-
-	allSelected := len(m.selectedVSCode) == len(m.vscodeExts)
-	checked := "[ ]"
-	checkStyle := uncheckedStyle
-	if allSelected && len(m.vscodeExts) > 0 {
-		checked = "[✓]"
-		checkStyle = checkedStyle
-	}
-
-	itemStyle := normalItemStyle
-	if m.cursor == 0 {
-		itemStyle = selectedItemStyle
+	labels := []string{
+		"Node.js version:",
+		"Python version:",
+		"Extra conda-forge packages (comma separated):",
+		"Platforms (comma separated):",
 	}
 
-	line := fmt.Sprintf("%s %s %s", cursor, checkStyle.Render(checked), itemStyle.Render("Select All"))
-	b.WriteString(line)
-	b.WriteString("\n\n")
-
-	// Render actual extensions
-	for i, ext := range m.vscodeExts {
-		cursor := " "
-		if m.cursor == i+1 {
-			cursor = ">"
-		}
-
-		checked := "[ ]"
-		checkStyle := uncheckedStyle
-		if m.selectedVSCode[ext] {
-			checked = "[✓]"
-			checkStyle = checkedStyle
-		}
-
+	for i, label := range labels {
 		itemStyle := normalItemStyle
-		if m.cursor == i+1 {
+		cursor := " "
+		if m.coreFocus == i {
 			itemStyle = selectedItemStyle
+			cursor = ">"
 		}
-
-		line := fmt.Sprintf("%s %s %s", cursor, checkStyle.Render(checked), itemStyle.Render(ext))
-		b.WriteString(line)
+		b.WriteString(fmt.Sprintf("%s %s", cursor, itemStyle.Render(label)))
 		b.WriteString("\n")
+		b.WriteString("  " + m.coreInputs[i].View())
+		b.WriteString("\n\n")
 	}
 
-	b.WriteString("\n")
-	help := helpStyle.Render("↑/k up • ↓/j down • space toggle • enter next • esc back • q quit")
+	if m.coreErr != nil {
+		b.WriteString(uncheckedStyle.Render(fmt.Sprintf("✗ %v", m.coreErr)))
+		b.WriteString("\n\n")
+	}
+
+	info := m.wrap(helpStyle, fmt.Sprintf("uv is always installed. Versions use conda match specs, e.g. 22.* or >=3.13.\nDetected platform: %s. Known platforms: %s.\nSettings are saved to the ai-menu config file.", m.platform, strings.Join(knownPlatforms, ", ")))
+	b.WriteString(info)
+	b.WriteString("\n\n")
+
+	help := m.wrap(helpStyle, "tab/↓ next field • shift+tab/↑ previous field • enter save • esc cancel")
 	b.WriteString(help)
 	b.WriteString("\n")
 
 	return b.String()
 }
 
-func (m model) renderSpecialTools() string {
+func (m model) renderPathInput() string {
 	var b strings.Builder
 
 	// Add top padding
 	b.WriteString("\n")
 
-	title := titleStyle.Render("🔧 Select Special Tools to Install")
+	title := titleStyle.Render("📁 Enter Installation Directory")
 	b.WriteString(title)
 	b.WriteString("\n\n")
 
-	// Add explanation
-	explanation := helpStyle.Render("These are tools that are commonly used in prompts to aid the AI CLI tool to do various\nthings. In some examples some of these tools are used by various MCP servers.\nAnd some are just some of Scott's favorites.")
-	b.WriteString(explanation)
+	// Parent directory field
+	pathLabelStyle := normalItemStyle
+	if m.pathFocus == 0 {
+		pathLabelStyle = selectedItemStyle
+	}
+	b.WriteString(pathLabelStyle.Render("Parent directory:"))
+	b.WriteString("\n")
+	b.WriteString(m.pathInput.View())
 	b.WriteString("\n\n")
 
-	// Add "Select All" option at the top
-	cursor := " "
-	if m.cursor == 0 {
-		cursor = ">"
+	// Environment name field
+	envLabelStyle := normalItemStyle
+	if m.pathFocus == 1 {
+		envLabelStyle = selectedItemStyle
 	}
+	b.WriteString(envLabelStyle.Render("Environment name:"))
+	b.WriteString("\n")
+	b.WriteString(m.envInput.View())
+	b.WriteString("\n\n")
 
-	allSelected := len(m.selectedSpecial) == len(m.specialTools)
-	checked := "[ ]"
-	checkStyle := uncheckedStyle
-	if allSelected && len(m.specialTools) > 0 {
-		checked = "[✓]"
-		checkStyle = checkedStyle
+	// Show the full path that will be created
+	currentPath := m.pathInput.Value()
+	if currentPath == "" {
+		currentPath = m.installPath
 	}
+	fullPath := envDirFor(currentPath, m.envInput.Value())
+	pathPreview := m.wrap(helpStyle, fmt.Sprintf("Installation path: %s", fullPath))
+	b.WriteString(pathPreview)
+	b.WriteString("\n")
 
-	itemStyle := normalItemStyle
-	if m.cursor == 0 {
-		itemStyle = selectedItemStyle
+	if m.pathErr != nil {
+		b.WriteString(uncheckedStyle.Render(fmt.Sprintf("✗ %v", m.pathErr)))
+		b.WriteString("\n")
 	}
 
-	line := fmt.Sprintf("%s %s %s", cursor, checkStyle.Render(checked), itemStyle.Render("Select All"))
-	b.WriteString(line)
+	// List known environments that can be selected with the arrow keys
+	if len(m.environments) > 0 {
+		b.WriteString("\n")
+		b.WriteString(summaryStyle.UnsetPadding().Render("Existing environments:"))
+		b.WriteString("\n")
+		for _, env := range m.environments {
+			marker := "  "
+			if env.Path == fullPath {
+				marker = "> "
+			}
+			b.WriteString(fmt.Sprintf("%s%s  %s\n", marker, env.Name, helpStyle.UnsetPadding().Render(env.Path)))
+		}
+	}
+	b.WriteString("\n")
+
+	infoText := m.wrap(helpStyle, fmt.Sprintf("A pixi environment with nodejs %s will be created at this location.\nSupports: %s", m.config.Core.NodeVersion, strings.Join(m.config.Core.Platforms, ", ")))
+	b.WriteString(infoText)
 	b.WriteString("\n\n")
 
-	// Render actual tools
-	for i, tool := range m.specialTools {
-		cursor := " "
-		if m.cursor == i+1 {
-			cursor = ">"
-		}
+	help := m.wrap(helpStyle, "tab switch field • ↑/↓ pick existing environment • enter confirm • esc back")
+	b.WriteString(help)
+	b.WriteString("\n")
 
-		checked := "[ ]"
-		checkStyle := uncheckedStyle
-		if m.selectedSpecial[tool] {
-			checked = "[✓]"
-			checkStyle = checkedStyle
-		}
+	return b.String()
+}
+
+func (m model) renderCredentials() string {
+	var b strings.Builder
 
+	// Add top padding
+	b.WriteString("\n")
+
+	title := titleStyle.Render("🔑 API Keys and Credentials")
+	b.WriteString(title)
+	b.WriteString("\n\n")
+
+	explanation := m.wrap(helpStyle, fmt.Sprintf("The selected tools read these variables. Values are stored in\n%s (mode 0600) and loaded by the aliases and shims.",
+		credentialsPath(envDirFor(m.installPath, m.envName))))
+	b.WriteString(explanation)
+	b.WriteString("\n\n")
+
+	for i, name := range m.credentialVars {
 		itemStyle := normalItemStyle
-		if m.cursor == i+1 {
+		cursor := " "
+		if m.credentialFocus == i {
 			itemStyle = selectedItemStyle
+			cursor = ">"
 		}
 
-		line := fmt.Sprintf("%s %s %s", cursor, checkStyle.Render(checked), itemStyle.Render(tool))
-		b.WriteString(line)
+		// Describe where a value would come from if the field is left empty
+		status := uncheckedStyle.Render("not set")
+		switch {
+		case m.credentialInputs[i].Value() != "":
+			status = checkedStyle.Render("new value")
+		case m.savedCredentials[name] != "":
+			status = checkedStyle.Render("saved")
+		case os.Getenv(name) != "":
+			status = summaryStyle.UnsetPadding().Render("found in your environment • ctrl+e to save it")
+		}
+
+		b.WriteString(fmt.Sprintf("%s %s %s %s", cursor, itemStyle.Render(name), helpStyle.UnsetPadding().Render("("+strings.Join(m.credentialUsers(name), ", ")+")"), status))
 		b.WriteString("\n")
+		b.WriteString("  " + m.credentialInputs[i].View())
+		b.WriteString("\n\n")
 	}
 
-	b.WriteString("\n")
-	help := helpStyle.Render("↑/k up • ↓/j down • space toggle • enter next • esc back • q quit")
+	help := m.wrap(helpStyle, "tab/↓ next field • shift+tab/↑ previous field • ctrl+e use current value • enter next • esc back")
 	b.WriteString(help)
 	b.WriteString("\n")
 
 	return b.String()
 }
 
-func (m model) renderCLIEnhancers() string {
+func (m model) renderShellTargets() string {
 	var b strings.Builder
 
 	// Add top padding
 	b.WriteString("\n")
 
-	title := titleStyle.Render("🔧 Select CLI Tool Enhancers")
+	title := titleStyle.Render("🐚 Select Shells for Aliases")
 	b.WriteString(title)
 	b.WriteString("\n\n")
 
-	// Add explanation
-	explanation := helpStyle.Render("These are additional tools that enhance your AI CLI vibe coding and extend functionality.")
-	b.WriteString(explanation)
-	b.WriteString("\n")
-	explanation2 := helpStyle.Render("Keep in mind you can only have one tool installed at a time as they clobber each other's functionality.")
-	b.WriteString(explanation2)
-	b.WriteString("\n")
-	explanation3 := helpStyle.Render("It may be best if you want to try a different CLI enhancer that you delete your ai-dev-pixi directory, and rebuild your devcontainer before you try a different CLI enhancer.")
-	b.WriteString(explanation3)
-	b.WriteString("\n\n")
-
-	// Add "Select All" option at the top
-	cursor := " "
-	if m.cursor == 0 {
-		cursor = ">"
+	explanation := m.wrap(helpStyle, "Aliases for the installed tools will be written for each selected shell.")
+	if m.shellMode == modeShims {
+		explanation = m.wrap(helpStyle, "The environment's shim directory will be added to PATH for each selected shell.\nShims also work in scripts, Makefiles and editor tasks.")
 	}
-
-	allSelected := len(m.selectedCLIEnhancers) == len(m.cliEnhancers)
-	checked := "[ ]"
-	checkStyle := uncheckedStyle
-	if allSelected && len(m.cliEnhancers) > 0 {
-		checked = "[✓]"
-		checkStyle = checkedStyle
-	}
-
-	itemStyle := normalItemStyle
-	if m.cursor == 0 {
-		itemStyle = selectedItemStyle
-	}
-
-	line := fmt.Sprintf("%s %s %s", cursor, checkStyle.Render(checked), itemStyle.Render("Select All"))
-	b.WriteString(line)
+	b.WriteString(explanation)
 	b.WriteString("\n\n")
 
-	// Render actual enhancers
-	for i, enhancer := range m.cliEnhancers {
+	for i, shell := range supportedShells {
 		cursor := " "
-		if m.cursor == i+1 {
+		if m.cursor == i {
 			cursor = ">"
 		}
 
 		checked := "[ ]"
 		checkStyle := uncheckedStyle
-		if m.selectedCLIEnhancers[enhancer] {
+		if m.shellTargets[shell] {
 			checked = "[✓]"
 			checkStyle = checkedStyle
 		}
 
 		itemStyle := normalItemStyle
-		if m.cursor == i+1 {
+		if m.cursor == i {
 			itemStyle = selectedItemStyle
 		}
 
-		line := fmt.Sprintf("%s %s %s", cursor, checkStyle.Render(checked), itemStyle.Render(enhancer))
+		// Describe where the aliases go and whether the shell is usable here
+		notes := []string{}
+		if rcPath, err := shellRCPath(shell); err == nil {
+			notes = append(notes, displayPath(rcPath))
+		}
+		if shell == m.loginShell {
+			notes = append(notes, "login shell")
+		}
+		if !m.installedShells[shell] {
+			notes = append(notes, "not installed")
+		}
+
+		line := fmt.Sprintf("%s %s %s %s", cursor, checkStyle.Render(checked), itemStyle.Render(shell), helpStyle.UnsetPadding().Render("("+strings.Join(notes, ", ")+")"))
 		b.WriteString(line)
 		b.WriteString("\n")
 	}
 
+	if len(m.shellTargets) == 0 {
+		b.WriteString("\n")
+		b.WriteString(uncheckedStyle.Render("Select at least one shell to continue."))
+		b.WriteString("\n")
+	}
+
+	// Integration mode
+	b.WriteString("\n")
+	b.WriteString(summaryStyle.UnsetPadding().Render("Mode: "))
+	if m.shellMode == modeShims {
+		b.WriteString(normalItemStyle.Render("shims on PATH (<environment>/bin)"))
+	} else {
+		b.WriteString(normalItemStyle.Render("shell aliases"))
+	}
+	b.WriteString("\n")
+
 	b.WriteString("\n")
-	help := helpStyle.Render("↑/k up • ↓/j down • space toggle • enter next • esc back • q quit")
+	help := m.wrap(helpStyle, "↑/k up • ↓/j down • space toggle • m aliases/shims • enter next • esc back • q quit")
 	b.WriteString(help)
 	b.WriteString("\n")
 
 	return b.String()
 }
 
-func (m model) renderPathInput() string {
+func (m model) renderAliasNames() string {
 	var b strings.Builder
 
 	// Add top padding
 	b.WriteString("\n")
 
-	title := titleStyle.Render("📁 Enter Installation Directory")
+	title := titleStyle.Render("🏷️  Review Alias Names")
 	b.WriteString(title)
 	b.WriteString("\n\n")
 
-	b.WriteString(normalItemStyle.Render("Enter the parent directory path (ai-dev-pixi will be created inside):"))
+	explanation := m.wrap(helpStyle, "These names will be defined for the installed tools. Rename or disable\nany that would shadow a command you already use.")
+	b.WriteString(explanation)
 	b.WriteString("\n\n")
 
-	// Show the text input
-	b.WriteString(m.pathInput.View())
-	b.WriteString("\n\n")
+	if len(m.aliasList) == 0 {
+		b.WriteString(m.wrap(helpStyle, "No aliases will be written for this selection."))
+		b.WriteString("\n")
+	}
 
-	// Show the full path that will be created
-	currentPath := m.pathInput.Value()
-	if currentPath == "" {
-		currentPath = m.installPath
+	rows := []string{}
+	cursorRow := 0
+	for i, alias := range m.aliasList {
+		cursor := " "
+		if m.cursor == i {
+			cursor = ">"
+			cursorRow = len(rows)
+		}
+
+		itemStyle := normalItemStyle
+		if m.cursor == i {
+			itemStyle = selectedItemStyle
+		}
+
+		name, enabled := m.aliasNames.Lookup(alias)
+		if !enabled {
+			rows = append(rows, fmt.Sprintf("%s %s %s", cursor, uncheckedStyle.Render("[ ]"), disabledItemStyle.Render(alias)))
+			continue
+		}
+
+		label := alias
+		if name != alias {
+			label = fmt.Sprintf("%s → %s", alias, name)
+		}
+		line := fmt.Sprintf("%s %s %s", cursor, checkedStyle.Render("[✓]"), itemStyle.Render(label))
+		if conflict, exists := m.aliasConflicts[name]; exists {
+			line += " " + summaryStyle.UnsetPadding().Render("⚠️  shadows "+conflict)
+		}
+		rows = append(rows, truncate(line, m.width))
+
+		// Show the name field below the alias being renamed
+		if m.editingAlias && m.cursor == i {
+			rows = append(rows, "    "+m.aliasInput.View())
+			cursorRow = len(rows) - 1
+		}
 	}
-	fullPath := currentPath + "/ai-dev-pixi"
-	pathPreview := helpStyle.Render(fmt.Sprintf("Installation path: %s", fullPath))
-	b.WriteString(pathPreview)
-	b.WriteString("\n\n")
 
-	infoText := helpStyle.Render("A pixi environment with nodejs 22.* will be created at this location.\nSupports: linux-64, linux-aarch64")
-	b.WriteString(infoText)
-	b.WriteString("\n\n")
+	var footer strings.Builder
+	if m.aliasErr != nil {
+		footer.WriteString("\n")
+		footer.WriteString(uncheckedStyle.Render(fmt.Sprintf("✗ %v", m.aliasErr)))
+		footer.WriteString("\n")
+	}
 
-	help := helpStyle.Render("enter confirm • esc back")
-	b.WriteString(help)
-	b.WriteString("\n")
+	footer.WriteString("\n")
+	help := m.wrap(helpStyle, "↑/k up • ↓/j down • space enable/disable • r rename • enter next • esc back • q quit")
+	if m.editingAlias {
+		help = m.wrap(helpStyle, "enter save name • esc cancel")
+	}
+	footer.WriteString(help)
+	footer.WriteString("\n")
+
+	// Scroll long alias lists with the cursor
+	for _, row := range scrollRows(rows, cursorRow, listHeight(m.height, b.String(), footer.String())) {
+		b.WriteString(row)
+		b.WriteString("\n")
+	}
+	b.WriteString(footer.String())
 
 	return b.String()
 }
 
 func (m model) renderInstallSummary() string {
-	var b strings.Builder
+	header, body, footer := m.installSummaryParts()
+	return m.renderPage(header, body, footer)
+}
 
+// installSummaryParts returns the title, the scrolling overview of the installation and the
+// profile field and help below it
+func (m model) installSummaryParts() (string, string, string) {
 	// Add top padding
-	b.WriteString("\n")
+	header := "\n" + titleStyle.Render("📦 Selected Software for Installation") + "\n\n"
 
-	title := titleStyle.Render("📦 Selected Software for Installation")
-	b.WriteString(title)
-	b.WriteString("\n\n")
+	var b strings.Builder
 
 	// Installation path
 	if len(m.selectedCLI) > 0 || len(m.selectedVSCode) > 0 || len(m.selectedSpecial) > 0 || len(m.selectedCLIEnhancers) > 0 {
 		b.WriteString(summaryStyle.Render("Installation Path:"))
 		b.WriteString("\n")
-		fullPath := m.installPath + "/ai-dev-pixi"
-		b.WriteString(fmt.Sprintf("  📁 %s\n", fullPath))
+		fullPath := envDirFor(m.installPath, m.envName)
+		b.WriteString(fmt.Sprintf("  📁 %s (environment: %s)\n", fullPath, m.envName))
 		b.WriteString("\n")
 	}
 
@@ -478,15 +624,106 @@ func (m model) renderInstallSummary() string {
 	}
 
 	if len(m.selectedCLI) == 0 && len(m.selectedVSCode) == 0 && len(m.selectedSpecial) == 0 && len(m.selectedCLIEnhancers) == 0 {
-		b.WriteString(helpStyle.Render("No items selected for installation."))
+		b.WriteString(m.wrap(helpStyle, "No items selected for installation."))
 		b.WriteString("\n\n")
 	}
 
-	help := helpStyle.Render("enter to start installation • esc back • q quit without installing")
-	b.WriteString(help)
+	// Shell targets
+	if m.shellMode == modeShims {
+		b.WriteString(summaryStyle.Render(fmt.Sprintf("Shims (%s on PATH):", shimBinDir(envDirFor(m.installPath, m.envName)))))
+	} else {
+		b.WriteString(summaryStyle.Render("Shell Aliases:"))
+	}
+	b.WriteString("\n")
+	for _, shell := range m.selectedShells() {
+		rcPath, _ := shellRCPath(shell)
+		b.WriteString(fmt.Sprintf("  • %s (%s)\n", shell, displayPath(rcPath)))
+	}
 	b.WriteString("\n")
 
-	return b.String()
+	// Credentials to be saved or missing
+	if len(m.credentialVars) > 0 {
+		entered := m.enteredCredentials()
+		b.WriteString(summaryStyle.Render("Credentials:"))
+		b.WriteString("\n")
+		for _, name := range m.credentialVars {
+			switch {
+			case entered[name] != "":
+				b.WriteString(checkedStyle.Render("  ✓ ") + normalItemStyle.Render(name+" (will be saved)"))
+			case m.savedCredentials[name] != "":
+				b.WriteString(checkedStyle.Render("  ✓ ") + normalItemStyle.Render(name+" (already saved)"))
+			case os.Getenv(name) != "":
+				b.WriteString(checkedStyle.Render("  ✓ ") + normalItemStyle.Render(name+" (from your environment)"))
+			default:
+				b.WriteString(uncheckedStyle.Render("  ✗ " + name + " (not set)"))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	// Aliases that would shadow existing commands
+	if conflicts := m.activeAliasConflicts(); len(conflicts) > 0 {
+		b.WriteString(summaryStyle.Render("⚠️  Alias Conflicts:"))
+		b.WriteString("\n")
+		for _, conflict := range conflicts {
+			b.WriteString(fmt.Sprintf("  • %s\n", conflict))
+		}
+		b.WriteString(helpStyle.UnsetPadding().Render("  Press esc to rename or disable these aliases."))
+		b.WriteString("\n\n")
+	}
+
+	// Problems found by the pre-flight check
+	if unpassed := unpassedChecks(m.preflight); len(unpassed) > 0 {
+		b.WriteString(summaryStyle.Render("Pre-flight Checks:"))
+		b.WriteString("\n")
+		for _, check := range unpassed {
+			style := helpStyle.UnsetPadding()
+			if check.Status == checkFail {
+				style = uncheckedStyle
+			}
+			b.WriteString(style.Render("  " + strings.ReplaceAll(formatCheck(check), "\n", "\n  ")))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	// Isolation mode
+	b.WriteString(summaryStyle.Render("Tool Isolation:"))
+	b.WriteString("\n")
+	if m.isolate {
+		b.WriteString(checkedStyle.Render("  [✓] ") + normalItemStyle.Render("Each npm/uv tool and enhancer gets its own pixi environment"))
+	} else {
+		b.WriteString(uncheckedStyle.Render("  [ ] ") + normalItemStyle.Render("All tools share the default pixi environment"))
+	}
+	b.WriteString("\n\n")
+
+	// Saving the selections as a profile stays in view below the overview
+	var footer strings.Builder
+	if m.savingProfile {
+		footer.WriteString(summaryStyle.Render("Save as Profile:"))
+		footer.WriteString("\n  " + m.profileInput.View() + "\n\n")
+	}
+	if m.profileMessage != "" {
+		if strings.HasPrefix(m.profileMessage, "✗") {
+			footer.WriteString(uncheckedStyle.Render(m.profileMessage))
+		} else {
+			footer.WriteString(checkedStyle.Render(m.profileMessage))
+		}
+		footer.WriteString("\n\n")
+	}
+
+	help := m.wrap(helpStyle, "enter to start installation • ↑/↓ scroll • i toggle isolation • p save as profile • esc back • q quit without installing")
+	if !doctorPassed(m.preflight) {
+		help = m.wrap(helpStyle, "fix the failed checks, then enter to check again • ↑/↓ scroll • i toggle isolation • p save as profile • esc back • q quit")
+	}
+	if m.savingProfile {
+		help = m.wrap(helpStyle, "enter save profile • esc cancel")
+	}
+	footer.WriteString(help)
+	footer.WriteString("\n")
+
+	return header, b.String(), footer.String()
 }
 
 func (m model) renderInstalling() string {
@@ -503,33 +740,38 @@ func (m model) renderInstalling() string {
 	b.WriteString(m.spinner.View())
 	b.WriteString(" Installing selected tools...\n\n")
 
-	// Show recent messages (last 10)
+	footer := "\n" + m.wrap(helpStyle, "Please wait... Installation in progress") + "\n"
+
+	// Show recent messages (last 10, fewer if the terminal is short)
+	count := 10
+	if height := listHeight(m.height, b.String(), footer); height > 0 {
+		count = min(count, height)
+	}
 	startIdx := 0
-	if len(m.installMessages) > 10 {
-		startIdx = len(m.installMessages) - 10
+	if len(m.installMessages) > count {
+		startIdx = len(m.installMessages) - count
 	}
 	for i := startIdx; i < len(m.installMessages); i++ {
-		b.WriteString(normalItemStyle.Render(m.installMessages[i]))
+		b.WriteString(truncate(normalItemStyle.Render(m.installMessages[i]), m.width))
 		b.WriteString("\n")
 	}
 
-	b.WriteString("\n")
-	help := helpStyle.Render("Please wait... Installation in progress")
-	b.WriteString(help)
-	b.WriteString("\n")
+	b.WriteString(footer)
 
 	return b.String()
 }
 
 func (m model) renderDone() string {
-	var b strings.Builder
+	header, body, footer := m.doneParts()
+	return m.renderPage(header, body, footer)
+}
 
+// doneParts returns the title, the scrolling results of the installation and the help below them
+func (m model) doneParts() (string, string, string) {
 	// Add top padding
-	b.WriteString("\n")
+	header := "\n" + titleStyle.Render("✅ Installation Complete!") + "\n\n"
 
-	title := titleStyle.Render("✅ Installation Complete!")
-	b.WriteString(title)
-	b.WriteString("\n\n")
+	var b strings.Builder
 
 	// Count successes and failures
 	successCount := 0
@@ -548,7 +790,7 @@ func (m model) renderDone() string {
 		b.WriteString("\n")
 	}
 	if failCount > 0 {
-		b.WriteString(helpStyle.Render(fmt.Sprintf("✗ %d tools failed to install", failCount)))
+		b.WriteString(m.wrap(helpStyle, fmt.Sprintf("✗ %d tools failed to install", failCount)))
 		b.WriteString("\n")
 	}
 	b.WriteString("\n")
@@ -561,7 +803,7 @@ func (m model) renderDone() string {
 			// Check if this is a CLI tool (npm package)
 			if len(m.selectedCLI) > 0 {
 				for cliTool := range m.selectedCLI {
-					if result.Name == cliTool || result.Name == strings.Split(cliTool, " - ")[0] {
+					if result.Name == getPackageNameForCLI(cliTool) {
 						cliToolInstalled = true
 						break
 					}
@@ -569,23 +811,49 @@ func (m model) renderDone() string {
 			}
 		} else {
 			b.WriteString(uncheckedStyle.Render(fmt.Sprintf("✗ %s: %v", result.Name, result.Error)))
+			if result.LogPath != "" {
+				b.WriteString("\n")
+				b.WriteString(helpStyle.UnsetPadding().Render("    log: " + result.LogPath))
+			}
 		}
 		b.WriteString("\n")
 	}
 
-	// Show reminder to source .zshrc only if CLI tools were successfully installed
+	// Show reminder to reload the shell configuration only if CLI tools were successfully installed
 	if cliToolInstalled {
 		b.WriteString("\n")
 		b.WriteString(summaryStyle.Render("⚠️  Important: To use the CLI tool aliases, run:"))
 		b.WriteString("\n")
-		b.WriteString(selectedItemStyle.Render("  source ~/.zshrc"))
-		b.WriteString("\n")
+		for _, shell := range m.selectedShells() {
+			b.WriteString(selectedItemStyle.Render(fmt.Sprintf("  %s", sourceHint(shell))))
+			b.WriteString(helpStyle.UnsetPadding().Render(fmt.Sprintf("  (%s)", shell)))
+			b.WriteString("\n")
+		}
 	}
 
-	b.WriteString("\n")
-	help := helpStyle.Render("Press enter or q to exit")
-	b.WriteString(help)
-	b.WriteString("\n")
+	footer := "\n" + m.wrap(helpStyle, "Press enter or q to exit • ↑/↓ scroll") + "\n"
 
-	return b.String()
+	return header, b.String(), footer
+}
+
+// renderPage renders a screen whose body scrolls by whole lines, using the cursor as the
+// offset of the first line shown
+func (m model) renderPage(header, body, footer string) string {
+	lines := m.pageLines(body)
+	visible := scrollLines(lines, m.cursor, listHeight(m.height, header, footer))
+	return header + strings.Join(visible, "\n") + "\n" + footer
+}
+
+// pageLines splits the body of a page into lines that each fit on one terminal row
+func (m model) pageLines(body string) []string {
+	lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = truncate(line, m.width)
+	}
+	return lines
+}
+
+// pageScrollLimit returns the largest cursor offset of a page screen
+func (m model) pageScrollLimit(header, body, footer string) int {
+	return maxScrollOffset(len(m.pageLines(body)), listHeight(m.height, header, footer))
 }